@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"kaizen-hq/config"
 	"kaizen-hq/internal/account"
 	"kaizen-hq/internal/client"
 	"kaizen-hq/internal/permission"
@@ -16,9 +17,15 @@ import (
 	"golang.org/x/term"
 )
 
-// * Seedsystem populates the database when first created with admin data
+// * Seedsystem populates the database when first created with admin data.
+// The admin account comes from the seed file and BOOTSTRAP_ADMIN_* variables,
+// prompting on the terminal only for whatever they leave out. Permissions and
+// roles are seeded, and the admin's roles resolved, before the account is
+// created, since creating it is what marks the system as bootstrapped.
 func SeedSystem(
 	ctx context.Context,
+	cfg *config.Config,
+	tornClient client.Client,
 	accountSvc *account.Service,
 	userSvc *user.Service,
 	roleSvc *role.Service,
@@ -38,77 +45,143 @@ func SeedSystem(
 	}
 	log.Println("Bootstrapping system...")
 
-	var adminEmail string
-	var adminAPIKey string
-
-	fmt.Print("Enter admin email: ")
-	fmt.Scanln(&adminEmail)
-
-	fmt.Print("Enter password: ")
-	passwordBytes, _ := term.ReadPassword(int(os.Stdin.Fd()))
-	adminPassword := string(passwordBytes)
+	seed, err := LoadSeed(cfg.Bootstrap)
+	if err != nil {
+		return err
+	}
 
-	fmt.Println()
+	if !seed.Admin.complete() {
+		if err := promptAdmin(&seed.Admin); err != nil {
+			return err
+		}
+	}
+	admin := seed.Admin
 
-	fmt.Print("Enter admin's api key: ")
-	fmt.Scanln(&adminAPIKey)
+	user, err := tornClient.FetchTornUser(ctx, admin.APIKey, "")
 
-	tornClient := client.NewClient()
+	if err != nil {
+		return err
+	}
 
-	user, err := tornClient.FetchTornUser(ctx, adminAPIKey, "")
+	discordID, err := tornClient.FetchDiscordID(ctx, admin.APIKey, user.PlayerID)
 
 	if err != nil {
 		return err
 	}
 
-	discordID, err := tornClient.FetchDiscordID(ctx, adminAPIKey, user.PlayerID)
+	// Step 1: Create the seed's permissions. Catalog permissions already
+	// exist since the catalog is reconciled first.
+	for _, p := range seed.Permissions {
+		if _, _, err := ensurePermission(ctx, permSvc, p); err != nil {
+			return err
+		}
+	}
+
+	// Step 2: Create roles and assign their permissions
+	for _, r := range seed.Roles {
+		created, _, err := ensureRole(ctx, roleSvc, r)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range r.Permissions {
+			perm, err := permSvc.GetByName(ctx, name)
+			if errors.Is(err, permission.ErrPermissionNotFound) {
+				return fmt.Errorf("role %s grants unknown permission %s", r.Name, name)
+			}
+			if err != nil {
+				return err
+			}
+			if err := roleSvc.AssignPermission(ctx, created, perm); err != nil {
+				return err
+			}
+		}
+	}
 
+	// Step 3: Look up the admin's roles, which may be seeded or default ones
+	adminRoles, err := resolveRoles(ctx, roleSvc, admin.Roles)
 	if err != nil {
-		discordID = ""
 		return err
 	}
 
-	_, err = userSvc.EnsureUserExists(ctx, strconv.Itoa(user.PlayerID), adminAPIKey)
+	// Step 4: Create root user and give them their roles
+	_, err = userSvc.EnsureUserExists(ctx, strconv.Itoa(user.PlayerID), admin.APIKey)
 	if err != nil {
 		return errors.New("error creating the user")
 	}
 
-	// Create root user
 	accountID, err := accountSvc.CreateAccount(ctx, &account.Account{
-		Email:     adminEmail,
+		Email:     admin.Email,
 		TornID:    user.PlayerID,
-		Password:  adminPassword,
-		APIKey:    adminAPIKey,
+		Password:  admin.Password,
+		APIKey:    admin.APIKey,
 		DiscordID: discordID,
 	})
-
 	if err != nil {
 		return err
 	}
 
-	// Step 1: Create roles
-	adminRole, err := roleSvc.Create(ctx, &role.Role{Name: "admin", Description: "Full access"})
-	if err != nil {
-		fmt.Println(err)
-		return err
+	for _, r := range adminRoles {
+		if err := accountSvc.AssignRole(ctx, accountID, r.ID); err != nil {
+			return err
+		}
 	}
 
-	// Step 2: Create permissions
-	logPerm, err := permSvc.Create(ctx, &permission.Permission{Name: "view_logs", Description: "Able to view logs"})
-	if err != nil {
-		fmt.Println(err)
-		return err
+	return nil
+}
+
+// roleFinder looks up roles by name
+type roleFinder interface {
+	GetByName(ctx context.Context, name string) (*role.Role, error)
+}
+
+// resolveRoles looks up each named role, failing on any that doesn't exist
+func resolveRoles(ctx context.Context, roles roleFinder, names []string) ([]*role.Role, error) {
+	resolved := make([]*role.Role, 0, len(names))
+	for _, name := range names {
+		r, err := roles.GetByName(ctx, name)
+		if errors.Is(err, role.ErrRoleNotFound) {
+			return nil, fmt.Errorf("admin is given unknown role %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, r)
 	}
+	return resolved, nil
+}
 
-	// Step 3: Assign permission to role
-	err = roleSvc.AssignPermission(ctx, adminRole, logPerm)
+// promptAdmin asks on the terminal for the admin details the seed left out,
+// failing instead of blocking when stdin isn't a terminal (e.g. in Docker)
+func promptAdmin(admin *AdminSeed) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return ErrNotInteractive
+	}
 
-	if err != nil {
-		fmt.Println(err)
+	if admin.Email == "" {
+		fmt.Print("Enter admin email: ")
+		fmt.Scanln(&admin.Email)
+	}
+
+	if admin.Password == "" {
+		fmt.Print("Enter password: ")
+		passwordBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		admin.Password = string(passwordBytes)
+
+		fmt.Println()
 	}
 
-	// Assign admin role
-	_ = accountSvc.AssignRole(ctx, accountID, adminRole.ID)
+	if admin.APIKey == "" {
+		fmt.Print("Enter admin's api key: ")
+		fmt.Scanln(&admin.APIKey)
+	}
+
+	if !admin.complete() {
+		return errors.New("admin email, password and api key are all required")
+	}
 
 	return nil
 }
@@ -0,0 +1,83 @@
+package bootstrap
+
+import (
+	"context"
+	"kaizen-hq/config"
+	"kaizen-hq/internal/role"
+	"slices"
+	"testing"
+)
+
+// fakeRoles finds the roles it was given by name
+type fakeRoles map[string]*role.Role
+
+func (f fakeRoles) GetByName(ctx context.Context, name string) (*role.Role, error) {
+	if r, ok := f[name]; ok {
+		return r, nil
+	}
+	return nil, role.ErrRoleNotFound
+}
+
+func TestResolveAdminRoles(t *testing.T) {
+	// The catalog's admin role always exists, alongside whatever the seed adds
+	existing := fakeRoles{
+		defaultAdminRole: {ID: 1, Name: defaultAdminRole},
+		"officer":        {ID: 2, Name: "officer"},
+	}
+
+	tests := []struct {
+		name    string
+		seed    string
+		wantIDs []int
+		wantErr bool
+	}{
+		{
+			name:    "seed roles without admin roles gives the default admin role",
+			seed:    "roles:\n  - name: officer\n",
+			wantIDs: []int{1},
+		},
+		{
+			name:    "seeded role",
+			seed:    "admin:\n  roles: [officer]\nroles:\n  - name: officer\n",
+			wantIDs: []int{2},
+		},
+		{
+			name:    "seeded and default roles",
+			seed:    "admin:\n  roles: [admin, officer]\nroles:\n  - name: officer\n",
+			wantIDs: []int{1, 2},
+		},
+		{
+			name:    "unknown role",
+			seed:    "admin:\n  roles: [banker]\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, err := LoadSeed(config.BootstrapConfig{SeedFile: writeSeed(t, "seed.yaml", tt.seed)})
+			if err != nil {
+				t.Fatalf("LoadSeed() returned error: %v", err)
+			}
+
+			roles, err := resolveRoles(context.Background(), existing, seed.Admin.Roles)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("resolveRoles() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveRoles() returned error: %v", err)
+			}
+
+			var ids []int
+			for _, r := range roles {
+				ids = append(ids, r.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("resolved role IDs %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
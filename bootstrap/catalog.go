@@ -0,0 +1,163 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaizen-hq/internal/permission"
+	"kaizen-hq/internal/role"
+	"slices"
+	"strings"
+)
+
+// DefaultRoles are the roles every installation has. Reconciling only adds
+// what is missing, so a default role keeps any extra permissions granted to it.
+var DefaultRoles = []RoleSeed{
+	{
+		Name:         defaultAdminRole,
+		Description:  "Full access",
+		IsLeadership: true,
+		Permissions:  permission.CatalogNames(),
+	},
+}
+
+// CatalogReport lists what reconciling the catalog changed or found
+type CatalogReport struct {
+	CreatedPermissions []string
+	CreatedRoles       []string
+	// GrantedPermissions are "role: permission" pairs added to existing default roles
+	GrantedPermissions []string
+	// LeadershipRoles are existing default roles that were marked as leadership
+	LeadershipRoles []string
+	// UnknownPermissions exist in the database but not in the catalog
+	UnknownPermissions []string
+}
+
+// ReconcileCatalog creates any catalog permission or default role that is
+// missing from the database, marks default leadership roles as such, and
+// reports permissions the catalog no longer knows about. It is safe to run on
+// every startup.
+func ReconcileCatalog(ctx context.Context, roleSvc *role.Service, permSvc *permission.Service) (*CatalogReport, error) {
+	report := &CatalogReport{}
+
+	perms := map[string]*permission.Permission{}
+	for _, p := range permission.Catalog {
+		perm, created, err := ensurePermission(ctx, permSvc, PermissionSeed{Name: p.Name, Description: p.Description})
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			report.CreatedPermissions = append(report.CreatedPermissions, p.Name)
+		}
+		perms[p.Name] = perm
+	}
+
+	existing, err := permSvc.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range existing {
+		if _, ok := perms[p.Name]; !ok {
+			report.UnknownPermissions = append(report.UnknownPermissions, p.Name)
+		}
+	}
+
+	for _, r := range DefaultRoles {
+		current, created, err := ensureRole(ctx, roleSvc, r)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			report.CreatedRoles = append(report.CreatedRoles, r.Name)
+		}
+
+		// Roles created before the leadership flag existed don't have it set
+		if r.IsLeadership && !current.IsLeadership {
+			current.IsLeadership = true
+			if err := roleSvc.Update(ctx, current); err != nil {
+				return nil, fmt.Errorf("failed to mark role %s as leadership: %w", r.Name, err)
+			}
+			report.LeadershipRoles = append(report.LeadershipRoles, r.Name)
+		}
+
+		held, err := roleSvc.ListPermissions(ctx, current.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range r.Permissions {
+			if slices.ContainsFunc(held, func(p permission.Permission) bool { return p.Name == name }) {
+				continue
+			}
+			if err := roleSvc.AssignPermission(ctx, current, perms[name]); err != nil {
+				return nil, err
+			}
+			if !created {
+				report.GrantedPermissions = append(report.GrantedPermissions, r.Name+": "+name)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// ensurePermission returns the permission with the seed's name, creating it if needed
+func ensurePermission(ctx context.Context, permSvc *permission.Service, p PermissionSeed) (*permission.Permission, bool, error) {
+	existing, err := permSvc.GetByName(ctx, p.Name)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, permission.ErrPermissionNotFound) {
+		return nil, false, err
+	}
+
+	created, err := permSvc.Create(ctx, &permission.Permission{Name: p.Name, Description: p.Description})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create permission %s: %w", p.Name, err)
+	}
+	return created, true, nil
+}
+
+// ensureRole returns the role with the seed's name, creating it if needed
+func ensureRole(ctx context.Context, roleSvc *role.Service, r RoleSeed) (*role.Role, bool, error) {
+	roles, err := roleSvc.List(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, existing := range roles {
+		if existing.Name == r.Name {
+			return existing, false, nil
+		}
+	}
+
+	created, err := roleSvc.Create(ctx, &role.Role{Name: r.Name, Description: r.Description, IsLeadership: r.IsLeadership})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create role %s: %w", r.Name, err)
+	}
+	return created, true, nil
+}
+
+// String summarises the report for the startup log
+func (r *CatalogReport) String() string {
+	var parts []string
+	if len(r.CreatedPermissions) > 0 {
+		parts = append(parts, "created permissions "+strings.Join(r.CreatedPermissions, ", "))
+	}
+	if len(r.CreatedRoles) > 0 {
+		parts = append(parts, "created roles "+strings.Join(r.CreatedRoles, ", "))
+	}
+	if len(r.GrantedPermissions) > 0 {
+		parts = append(parts, "granted "+strings.Join(r.GrantedPermissions, ", "))
+	}
+	if len(r.LeadershipRoles) > 0 {
+		parts = append(parts, "marked as leadership "+strings.Join(r.LeadershipRoles, ", "))
+	}
+	if len(r.UnknownPermissions) > 0 {
+		parts = append(parts, "not in the catalog: "+strings.Join(r.UnknownPermissions, ", "))
+	}
+	if len(parts) == 0 {
+		return "catalog up to date"
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,97 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kaizen-hq/config"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotInteractive is returned when the admin account is incomplete and stdin can't be prompted
+var ErrNotInteractive = errors.New("bootstrap needs an admin account but stdin is not a terminal: " +
+	"set BOOTSTRAP_ADMIN_EMAIL, BOOTSTRAP_ADMIN_PASSWORD and BOOTSTRAP_ADMIN_API_KEY, or point BOOTSTRAP_SEED_FILE at a seed file")
+
+// Seed describes the data a fresh system starts with, on top of the
+// permission catalog and default roles
+type Seed struct {
+	Admin       AdminSeed        `json:"admin" yaml:"admin"`
+	Permissions []PermissionSeed `json:"permissions" yaml:"permissions"`
+	Roles       []RoleSeed       `json:"roles" yaml:"roles"`
+}
+
+// AdminSeed is the first account, which is given the listed roles
+type AdminSeed struct {
+	Email    string   `json:"email" yaml:"email"`
+	Password string   `json:"password" yaml:"password"`
+	APIKey   string   `json:"api_key" yaml:"api_key"`
+	Roles    []string `json:"roles" yaml:"roles"`
+}
+
+type PermissionSeed struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+type RoleSeed struct {
+	Name         string   `json:"name" yaml:"name"`
+	Description  string   `json:"description" yaml:"description"`
+	IsLeadership bool     `json:"is_leadership" yaml:"is_leadership"`
+	Permissions  []string `json:"permissions" yaml:"permissions"`
+}
+
+// complete reports whether every admin field needed to create the account is set
+func (a *AdminSeed) complete() bool {
+	return a.Email != "" && a.Password != "" && a.APIKey != ""
+}
+
+// defaultAdminRole is the role the admin is given when the seed file doesn't list any roles
+const defaultAdminRole = "admin"
+
+// LoadSeed builds the seed from the configured seed file, if any, with the
+// BOOTSTRAP_ADMIN_* variables taking precedence over the file's admin account
+func LoadSeed(cfg config.BootstrapConfig) (*Seed, error) {
+	seed := &Seed{}
+
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read seed file: %w", err)
+		}
+
+		switch filepath.Ext(cfg.SeedFile) {
+		case ".json":
+			err = json.Unmarshal(data, seed)
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(data, seed)
+		default:
+			return nil, fmt.Errorf("seed file %s must be .json, .yaml or .yml", cfg.SeedFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse seed file: %w", err)
+		}
+	}
+
+	if cfg.AdminEmail != "" {
+		seed.Admin.Email = cfg.AdminEmail
+	}
+	if cfg.AdminPassword != "" {
+		seed.Admin.Password = cfg.AdminPassword
+	}
+	if cfg.AdminAPIKey != "" {
+		seed.Admin.APIKey = cfg.AdminAPIKey
+	}
+
+	if len(seed.Roles) == 0 {
+		seed.Roles = DefaultRoles
+	}
+
+	if len(seed.Admin.Roles) == 0 {
+		seed.Admin.Roles = []string{defaultAdminRole}
+	}
+
+	return seed, nil
+}
@@ -0,0 +1,131 @@
+package bootstrap
+
+import (
+	"kaizen-hq/config"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const yamlSeed = `admin:
+  email: file@example.com
+  password: file-password
+  api_key: file-key
+  roles: [officer]
+permissions:
+  - name: war.manage
+    description: Plan wars
+roles:
+  - name: officer
+    description: Runs wars
+    permissions: [war.manage]
+`
+
+const jsonSeed = `{"admin": {"email": "file@example.com", "password": "file-password", "api_key": "file-key"}}`
+
+func writeSeed(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSeed(t *testing.T) {
+	officer := []RoleSeed{{Name: "officer", Description: "Runs wars", Permissions: []string{"war.manage"}}}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		seedFile  string
+		wantAdmin AdminSeed
+		wantRoles []RoleSeed
+		wantPerms []PermissionSeed
+		wantErr   bool
+	}{
+		{
+			name: "env only",
+			env: map[string]string{
+				"BOOTSTRAP_ADMIN_EMAIL":    "env@example.com",
+				"BOOTSTRAP_ADMIN_PASSWORD": "env-password",
+				"BOOTSTRAP_ADMIN_API_KEY":  "env-key",
+			},
+			wantAdmin: AdminSeed{Email: "env@example.com", Password: "env-password", APIKey: "env-key", Roles: []string{defaultAdminRole}},
+			wantRoles: DefaultRoles,
+		},
+		{
+			name:      "nothing set",
+			wantAdmin: AdminSeed{Roles: []string{defaultAdminRole}},
+			wantRoles: DefaultRoles,
+		},
+		{
+			name:      "yaml file",
+			seedFile:  writeSeed(t, "seed.yaml", yamlSeed),
+			wantAdmin: AdminSeed{Email: "file@example.com", Password: "file-password", APIKey: "file-key", Roles: []string{"officer"}},
+			wantRoles: officer,
+			wantPerms: []PermissionSeed{{Name: "war.manage", Description: "Plan wars"}},
+		},
+		{
+			name:      "json file",
+			seedFile:  writeSeed(t, "seed.json", jsonSeed),
+			wantAdmin: AdminSeed{Email: "file@example.com", Password: "file-password", APIKey: "file-key", Roles: []string{defaultAdminRole}},
+			wantRoles: DefaultRoles,
+		},
+		{
+			name:      "env overrides file",
+			env:       map[string]string{"BOOTSTRAP_ADMIN_PASSWORD": "env-password"},
+			seedFile:  writeSeed(t, "seed.yml", yamlSeed),
+			wantAdmin: AdminSeed{Email: "file@example.com", Password: "env-password", APIKey: "file-key", Roles: []string{"officer"}},
+			wantRoles: officer,
+			wantPerms: []PermissionSeed{{Name: "war.manage", Description: "Plan wars"}},
+		},
+		{
+			name:     "unknown extension",
+			seedFile: writeSeed(t, "seed.toml", yamlSeed),
+			wantErr:  true,
+		},
+		{
+			name:     "malformed file",
+			seedFile: writeSeed(t, "seed.json", "{"),
+			wantErr:  true,
+		},
+		{
+			name:     "missing file",
+			seedFile: filepath.Join(t.TempDir(), "missing.yaml"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"BOOTSTRAP_ADMIN_EMAIL", "BOOTSTRAP_ADMIN_PASSWORD", "BOOTSTRAP_ADMIN_API_KEY"} {
+				t.Setenv(key, tt.env[key])
+			}
+			t.Setenv("BOOTSTRAP_SEED_FILE", tt.seedFile)
+
+			seed, err := LoadSeed(config.Load().Bootstrap)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadSeed() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSeed() returned error: %v", err)
+			}
+
+			if !reflect.DeepEqual(seed.Admin, tt.wantAdmin) {
+				t.Errorf("admin = %+v, want %+v", seed.Admin, tt.wantAdmin)
+			}
+			if !reflect.DeepEqual(seed.Roles, tt.wantRoles) {
+				t.Errorf("roles = %+v, want %+v", seed.Roles, tt.wantRoles)
+			}
+			if !reflect.DeepEqual(seed.Permissions, tt.wantPerms) {
+				t.Errorf("permissions = %+v, want %+v", seed.Permissions, tt.wantPerms)
+			}
+		})
+	}
+}
@@ -3,10 +3,19 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 type TornAPIConfig struct {
 	BaseURL string
+	// Version is the Torn API version used unless a call picks its own: "" for v1 or "v2"
+	Version string
+	// KeyRateLimit is the number of requests each key may make per minute
+	KeyRateLimit int
+	// KeyMaxWait is how long a request may queue for a key below its rate limit
+	KeyMaxWait time.Duration
+	// CachePersist keeps cached responses in Postgres so they survive restarts
+	CachePersist bool
 }
 
 type CorsConfig struct {
@@ -14,6 +23,14 @@ type CorsConfig struct {
 	ClientPort   string
 }
 
+// BootstrapConfig describes the first admin account when seeding without a terminal
+type BootstrapConfig struct {
+	SeedFile      string
+	AdminEmail    string
+	AdminPassword string
+	AdminAPIKey   string
+}
+
 type Config struct {
 	DBURL           string
 	JWTSecret       string
@@ -21,6 +38,7 @@ type Config struct {
 	DiscordBotToken string
 	TornAPI         TornAPIConfig
 	CORS            CorsConfig
+	Bootstrap       BootstrapConfig
 }
 
 func Load() *Config {
@@ -31,12 +49,22 @@ func Load() *Config {
 		BcryptCost:      getInt("BCRYPT_COST", 10),
 		DiscordBotToken: os.Getenv("DISCORD_BOT_TOKEN"),
 		TornAPI: TornAPIConfig{
-			BaseURL: "https://api.torn.com/",
+			BaseURL:      "https://api.torn.com/",
+			Version:      os.Getenv("TORN_API_VERSION"),
+			KeyRateLimit: getInt("TORN_KEY_RATE_LIMIT", 100),
+			KeyMaxWait:   time.Duration(getInt("TORN_KEY_MAX_WAIT_SECONDS", 30)) * time.Second,
+			CachePersist: getBool("TORN_CACHE_PERSIST", false),
 		},
 		CORS: CorsConfig{
 			ClientDomain: os.Getenv("CLIENT_DOMAIN"),
 			ClientPort:   os.Getenv("CLIENT_PORT"),
 		},
+		Bootstrap: BootstrapConfig{
+			SeedFile:      os.Getenv("BOOTSTRAP_SEED_FILE"),
+			AdminEmail:    os.Getenv("BOOTSTRAP_ADMIN_EMAIL"),
+			AdminPassword: os.Getenv("BOOTSTRAP_ADMIN_PASSWORD"),
+			AdminAPIKey:   os.Getenv("BOOTSTRAP_ADMIN_API_KEY"),
+		},
 	}
 }
 
@@ -49,3 +77,13 @@ func getInt(key string, defaultValue int) int {
 
 	return defaultValue
 }
+
+func getBool(key string, defaultValue bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+
+	return defaultValue
+}
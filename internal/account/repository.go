@@ -10,7 +10,10 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrNoFactionAPIKey = errors.New("no stored api key with faction access")
+)
 
 type Repository struct {
 	db *pgxpool.Pool
@@ -37,7 +40,7 @@ func (r *Repository) CreateAccount(ctx context.Context, account *Account) (int,
 func (r *Repository) GetAccountByTornID(ctx context.Context, tornID int) (*Account, error) {
 	user := &Account{}
 
-	query := `SELECT id, torn_id, email, api_key, created_at FROM users WHERE torn_id = $1`
+	query := `SELECT id, torn_id, email, api_key, created_at FROM accounts WHERE torn_id = $1`
 	err := r.db.QueryRow(ctx, query, tornID).Scan(
 		&user.ID,
 		&user.TornID,
@@ -80,6 +83,89 @@ func (r *Repository) GetAccountByEmail(ctx context.Context, email string) (*Acco
 	return account, nil
 }
 
+// GetAccountByDiscordID finds the account linked to a Discord user
+func (r *Repository) GetAccountByDiscordID(ctx context.Context, discordID string) (*Account, error) {
+	account := &Account{}
+
+	query := `SELECT id, torn_id, email, api_key, discord_id, created_at FROM accounts WHERE discord_id = $1`
+
+	err := r.db.QueryRow(ctx, query, discordID).Scan(
+		&account.ID,
+		&account.TornID,
+		&account.Email,
+		&account.APIKey,
+		&account.DiscordID,
+		&account.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// LinkDiscord stores the Discord ID on the account with the given Torn ID, if any
+func (r *Repository) LinkDiscord(ctx context.Context, tornID int, discordID string) error {
+	query := `UPDATE accounts SET discord_id = $1 WHERE torn_id = $2`
+
+	_, err := r.db.Exec(ctx, query, discordID, tornID)
+	return err
+}
+
+// FactionAPIKeys lists the stored API keys of accounts holding a leadership role.
+// Position sync marks the roles of positions with faction API access as leadership.
+func (r *Repository) FactionAPIKeys(ctx context.Context) ([]string, error) {
+	query := `SELECT DISTINCT a.api_key
+		FROM accounts a
+		JOIN user_roles ur ON ur.user_id = a.id
+		JOIN roles ro ON ro.id = ur.role_id
+		WHERE ro.is_leadership AND a.api_key <> '' AND a.api_key_invalid_at IS NULL`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// UpdateAPIKey stores a new key on the account with the given Torn ID and clears any invalid mark
+func (r *Repository) UpdateAPIKey(ctx context.Context, tornID int, apiKey string) error {
+	query := `UPDATE accounts SET api_key = $1, api_key_invalid_at = NULL WHERE torn_id = $2`
+
+	tag, err := r.db.Exec(ctx, query, apiKey, tornID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// InvalidateAPIKey marks the key as no longer working and returns the accounts
+// that stored it. Accounts whose key was already marked are not returned again.
+func (r *Repository) InvalidateAPIKey(ctx context.Context, apiKey string) ([]Account, error) {
+	query := `UPDATE accounts SET api_key_invalid_at = NOW()
+		WHERE api_key = $1 AND api_key_invalid_at IS NULL
+		RETURNING id, torn_id, email, discord_id`
+
+	rows, err := r.db.Query(ctx, query, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Account, error) {
+		var a Account
+		err := row.Scan(&a.ID, &a.TornID, &a.Email, &a.DiscordID)
+		return a, err
+	})
+}
+
 // Count gives the number of users recorded in the database
 func (r *Repository) Count(ctx context.Context) (int, error) {
 	var count int
@@ -98,3 +184,12 @@ func (r *Repository) AssignRole(ctx context.Context, userID, roleID int) error {
 
 	return err
 }
+
+// RevokeRole removes a role from a user
+func (r *Repository) RevokeRole(ctx context.Context, userID, roleID int) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`
+
+	_, err := r.db.Exec(ctx, query, userID, roleID)
+
+	return err
+}
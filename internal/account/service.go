@@ -44,6 +44,37 @@ func (s *Service) GetAccountByEmail(
 	return user, nil
 }
 
+func (s *Service) GetAccountByDiscordID(ctx context.Context, discordID string) (*Account, error) {
+	return s.repo.GetAccountByDiscordID(ctx, discordID)
+}
+
+func (s *Service) LinkDiscord(ctx context.Context, tornID int, discordID string) error {
+	return s.repo.LinkDiscord(ctx, tornID, discordID)
+}
+
+// FactionAPIKeys returns the stored keys that can be used for faction selections
+func (s *Service) FactionAPIKeys(ctx context.Context) ([]string, error) {
+	keys, err := s.repo.FactionAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoFactionAPIKey
+	}
+
+	return keys, nil
+}
+
+func (s *Service) UpdateAPIKey(ctx context.Context, tornID int, apiKey string) error {
+	return s.repo.UpdateAPIKey(ctx, tornID, apiKey)
+}
+
+// InvalidateAPIKey stops a key Torn rejected from being used, returning the accounts that stored it
+func (s *Service) InvalidateAPIKey(ctx context.Context, apiKey string) ([]Account, error) {
+	return s.repo.InvalidateAPIKey(ctx, apiKey)
+}
+
 func (s *Service) Count(ctx context.Context) (int, error) {
 	return s.repo.Count(ctx)
 }
@@ -68,3 +99,7 @@ func (s *Service) CreateAccount(ctx context.Context, account *Account) (int, err
 func (s *Service) AssignRole(ctx context.Context, accountID, roleID int) error {
 	return s.repo.AssignRole(ctx, accountID, roleID)
 }
+
+func (s *Service) RevokeRole(ctx context.Context, accountID, roleID int) error {
+	return s.repo.RevokeRole(ctx, accountID, roleID)
+}
@@ -0,0 +1,21 @@
+package apicache
+
+import (
+	"kaizen-hq/internal/client"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	cache *client.Cache
+}
+
+func NewHandler(cache *client.Cache) *Handler {
+	return &Handler{cache: cache}
+}
+
+// Stats reports how often the Torn response cache served each selection
+func (h *Handler) Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"cache": h.cache.Stats()})
+}
@@ -0,0 +1,56 @@
+package apicache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository is a client.CacheStore keeping Torn responses in Postgres
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Get returns the stored response for the key, or nil if there is none
+func (r *Repository) Get(ctx context.Context, key string) ([]byte, time.Time, error) {
+	var (
+		value     []byte
+		expiresAt time.Time
+	)
+
+	query := `SELECT value, expires_at FROM torn_cache WHERE key = $1`
+	err := r.db.QueryRow(ctx, query, key).Scan(&value, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return value, expiresAt, nil
+}
+
+// Set stores a response, replacing any earlier one for the key
+func (r *Repository) Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	query := `INSERT INTO torn_cache (key, value, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`
+
+	_, err := r.db.Exec(ctx, query, key, value, expiresAt)
+	return err
+}
+
+// PurgeExpired deletes stored responses past their expiry, returning how many were removed
+func (r *Repository) PurgeExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM torn_cache WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
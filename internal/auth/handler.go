@@ -27,6 +27,7 @@ func (h *Handler) handleRegistrationError(c *gin.Context, err error) {
 		ErrInvalidAPIKey:          {http.StatusBadRequest, ErrInvalidAPIKey},
 		ErrInvalidAPIKeyAccess:    {http.StatusBadRequest, ErrInvalidAPIKeyAccess},
 		ErrUserNotFound:           {http.StatusBadRequest, ErrUserNotFound},
+		ErrAPIKeyWrongUser:        {http.StatusBadRequest, ErrAPIKeyWrongUser},
 	}
 
 	if mappedError, exists := errorMap[err.Error()]; exists {
@@ -62,6 +63,22 @@ func (h *Handler) Register(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"status": "user created"})
 }
 
+// UpdateAPIKey stores a new API key on the signed in player's account
+func (h *Handler) UpdateAPIKey(c *gin.Context) {
+	var req APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.UpdateAPIKey(c.Request.Context(), c.Keys["torn_id"].(int), req.APIKey); err != nil {
+		h.handleRegistrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "api key updated"})
+}
+
 func (h *Handler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -3,6 +3,8 @@ package auth
 import (
 	"fmt"
 	"kaizen-hq/config"
+	"kaizen-hq/internal/permission"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -35,6 +37,52 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	}
 }
 
+// grantsKey is the context key the caller's roles and permissions are cached under
+const grantsKey = "grants"
+
+// RequirePermission only lets through callers holding every one of the named
+// permissions. It must run after AuthMiddleware.
+func RequirePermission(permSvc *permission.Service, names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		grants, err := CallerGrants(c, permSvc)
+		if err != nil {
+			log.Printf("Error loading permissions: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to load permissions"})
+			return
+		}
+
+		for _, name := range names {
+			if !grants.Has(name) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing permission: " + name})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// CallerGrants returns the roles and permissions of the logged in caller,
+// loading them at most once per request
+func CallerGrants(c *gin.Context, permSvc *permission.Service) (*permission.Grants, error) {
+	if cached, ok := c.Get(grantsKey); ok {
+		return cached.(*permission.Grants), nil
+	}
+
+	tornID, ok := c.Get("torn_id")
+	if !ok {
+		return nil, fmt.Errorf("request is not authenticated")
+	}
+
+	grants, err := permSvc.GrantsForTornID(c.Request.Context(), tornID.(int))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(grantsKey, grants)
+	return grants, nil
+}
+
 // ValidateToken checks if a token is valid and returns the claims
 func validateToken(cfg *config.Config, tokenString string) (*Claims, error) {
 	// Parse token
@@ -13,6 +13,10 @@ type RegisterRequest struct {
 	APIKey   string `json:"api_key" binding:"required"`
 }
 
+type APIKeyRequest struct {
+	APIKey string `json:"api_key" binding:"required"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -20,6 +20,7 @@ const (
 	ErrUserNotFound           = "trouble finding the user in torn"
 	ErrUserAlreadyRegistered  = "the user with this api key is already registered"
 	ErrAccountCreationFailed  = "failed to create new account"
+	ErrAPIKeyWrongUser        = "the api key belongs to a different torn user"
 )
 
 type Service struct {
@@ -93,6 +94,30 @@ func (s *Service) Register(ctx context.Context, account *account.Account) error
 	return nil
 }
 
+// UpdateAPIKey replaces the key stored on the player's account, which also
+// puts a key that was marked invalid back into use
+func (s *Service) UpdateAPIKey(ctx context.Context, tornID int, apiKey string) error {
+	tornUser, err := s.fetchTornUser(ctx, apiKey)
+	if err != nil {
+		return errors.New(ErrInvalidAPIKey)
+	}
+
+	if tornUser.PlayerID != tornID {
+		return errors.New(ErrAPIKeyWrongUser)
+	}
+
+	accessLevel, err := s.verifyAPIKey(ctx, apiKey)
+	if err != nil {
+		return errors.New(ErrInvalidAPIKey)
+	}
+
+	if accessLevel < 3 {
+		return errors.New(ErrInvalidAPIKeyAccess)
+	}
+
+	return s.accountService.UpdateAPIKey(ctx, tornID, apiKey)
+}
+
 func (s *Service) Login(ctx context.Context, req *LoginRequest) (string, error) {
 	user, err := s.accountService.GetAccountByEmail(ctx, req.Email)
 	if err != nil {
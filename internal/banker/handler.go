@@ -0,0 +1,42 @@
+package banker
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultListLimit is how many requests are returned when no limit is given
+const defaultListLimit = 50
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListRequests returns the banker request ledger, newest first
+func (h *Handler) ListRequests(c *gin.Context) {
+	status := Status(c.Query("status"))
+
+	limit := defaultListLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive whole number"})
+			return
+		}
+		limit = parsed
+	}
+
+	requests, err := h.service.ListRequests(c.Request.Context(), status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
@@ -0,0 +1,86 @@
+package banker
+
+import (
+	"slices"
+	"time"
+)
+
+// Status describes where a banker request is in its lifecycle
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusClaimed   Status = "claimed"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+	StatusExpired   Status = "expired"
+	StatusVerified  Status = "verified"
+)
+
+// transitions lists the statuses each status may legally move to
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusClaimed, StatusCancelled, StatusExpired},
+	StatusClaimed:   {StatusCompleted, StatusFailed, StatusCancelled},
+	StatusCompleted: {StatusVerified},
+}
+
+// CanTransitionTo reports whether a request may move from s to next
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(transitions[s], next)
+}
+
+// IsFinal reports whether no further transitions are possible
+func (s Status) IsFinal() bool {
+	return len(transitions[s]) == 0
+}
+
+type Request struct {
+	ID                 int        `json:"id"`
+	GuildID            string     `json:"guild_id"`
+	RequesterDiscordID string     `json:"requester_discord_id"`
+	RequesterName      string     `json:"requester_name"`
+	RequesterTornID    int        `json:"requester_torn_id"`
+	Amount             int64      `json:"amount"`
+	Status             Status     `json:"status"`
+	HandlerDiscordID   string     `json:"handler_discord_id"`
+	HandlerName        string     `json:"handler_name"`
+	ClosedByDiscordID  string     `json:"closed_by_discord_id"`
+	ClosedByName       string     `json:"closed_by_name"`
+	AdminChannelID     string     `json:"admin_channel_id"`
+	AdminMessageID     string     `json:"admin_message_id"`
+	VerifiedNewsID     string     `json:"verified_news_id,omitempty"`
+	FlagReason         string     `json:"flag_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	ClaimedAt          *time.Time `json:"claimed_at,omitempty"`
+	ClosedAt           *time.Time `json:"closed_at,omitempty"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+}
+
+// Requester is a Discord member resolved to their Torn account and vault balance
+type Requester struct {
+	TornID  int
+	Balance int64
+}
+
+// Payout is a "was given $N by" entry parsed from the faction funds news
+type Payout struct {
+	NewsID      string
+	RecipientID int
+	SenderID    int
+	Amount      int64
+	Time        time.Time
+}
+
+// Flag describes a completed request whose payout could not be matched cleanly
+type Flag struct {
+	Request *Request
+	Reason  string
+}
+
+// VerificationResult summarises one pass of payout verification
+type VerificationResult struct {
+	Verified []*Request
+	Flagged  []Flag
+}
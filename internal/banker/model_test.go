@@ -0,0 +1,53 @@
+package banker
+
+import "testing"
+
+func TestStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want bool
+	}{
+		{StatusPending, StatusClaimed, true},
+		{StatusPending, StatusCancelled, true},
+		{StatusPending, StatusExpired, true},
+		{StatusPending, StatusCompleted, false},
+		{StatusClaimed, StatusCompleted, true},
+		{StatusClaimed, StatusFailed, true},
+		{StatusClaimed, StatusCancelled, true},
+		{StatusClaimed, StatusExpired, false},
+		{StatusCompleted, StatusVerified, true},
+		{StatusCompleted, StatusCancelled, false},
+		{StatusFailed, StatusClaimed, false},
+		{StatusCancelled, StatusPending, false},
+		{StatusExpired, StatusClaimed, false},
+		{StatusVerified, StatusCompleted, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestStatusIsFinal(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   bool
+	}{
+		{StatusPending, false},
+		{StatusClaimed, false},
+		{StatusCompleted, false},
+		{StatusFailed, true},
+		{StatusCancelled, true},
+		{StatusExpired, true},
+		{StatusVerified, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.IsFinal(); got != tt.want {
+			t.Errorf("%s.IsFinal() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
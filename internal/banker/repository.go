@@ -0,0 +1,220 @@
+package banker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrRequestNotFound = errors.New("banker request not found")
+
+const requestColumns = `id, guild_id, requester_discord_id, requester_name, requester_torn_id, amount, status,
+	handler_discord_id, handler_name, closed_by_discord_id, closed_by_name,
+	admin_channel_id, admin_message_id, verified_news_id, flag_reason,
+	created_at, updated_at, claimed_at, closed_at, verified_at`
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func scanRequest(row pgx.Row) (*Request, error) {
+	req := &Request{}
+
+	err := row.Scan(
+		&req.ID,
+		&req.GuildID,
+		&req.RequesterDiscordID,
+		&req.RequesterName,
+		&req.RequesterTornID,
+		&req.Amount,
+		&req.Status,
+		&req.HandlerDiscordID,
+		&req.HandlerName,
+		&req.ClosedByDiscordID,
+		&req.ClosedByName,
+		&req.AdminChannelID,
+		&req.AdminMessageID,
+		&req.VerifiedNewsID,
+		&req.FlagReason,
+		&req.CreatedAt,
+		&req.UpdatedAt,
+		&req.ClaimedAt,
+		&req.ClosedAt,
+		&req.VerifiedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// CreateRequest stores a new banker request and fills in its ID and timestamps
+func (r *Repository) CreateRequest(ctx context.Context, req *Request) error {
+	query := `INSERT INTO banker_requests
+		(guild_id, requester_discord_id, requester_name, requester_torn_id, amount, status, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+	RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(ctx, query,
+		req.GuildID,
+		req.RequesterDiscordID,
+		req.RequesterName,
+		req.RequesterTornID,
+		req.Amount,
+		req.Status,
+		time.Now(),
+	).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create banker request: %w", err)
+	}
+
+	return nil
+}
+
+// GetRequestByID finds a banker request by its ID
+func (r *Repository) GetRequestByID(ctx context.Context, id int) (*Request, error) {
+	query := `SELECT ` + requestColumns + ` FROM banker_requests WHERE id = $1`
+
+	return scanRequest(r.db.QueryRow(ctx, query, id))
+}
+
+// SetAdminMessage records where the admin copy of the request was posted
+func (r *Repository) SetAdminMessage(ctx context.Context, id int, channelID, messageID string) error {
+	query := `UPDATE banker_requests
+		SET admin_channel_id = $1, admin_message_id = $2, updated_at = $3
+		WHERE id = $4`
+
+	_, err := r.db.Exec(ctx, query, channelID, messageID, time.Now(), id)
+	return err
+}
+
+// Claim assigns a pending request to a banker. It returns ErrRequestNotFound
+// when the request is missing or is no longer pending.
+func (r *Repository) Claim(ctx context.Context, id int, handlerID, handlerName string) (*Request, error) {
+	now := time.Now()
+
+	query := `UPDATE banker_requests
+		SET status = $1, handler_discord_id = $2, handler_name = $3, updated_at = $4, claimed_at = $4
+		WHERE id = $5 AND status = $6
+		RETURNING ` + requestColumns
+
+	return scanRequest(r.db.QueryRow(ctx, query, StatusClaimed, handlerID, handlerName, now, id, StatusPending))
+}
+
+// Close moves a request from one status to a final status. The update only
+// applies while the request is still in the expected status, so concurrent
+// button presses cannot both succeed.
+func (r *Repository) Close(ctx context.Context, id int, from, to Status, closedByID, closedByName string) (*Request, error) {
+	now := time.Now()
+
+	query := `UPDATE banker_requests
+		SET status = $1, closed_by_discord_id = $2, closed_by_name = $3, updated_at = $4, closed_at = $4
+		WHERE id = $5 AND status = $6
+		RETURNING ` + requestColumns
+
+	return scanRequest(r.db.QueryRow(ctx, query, to, closedByID, closedByName, now, id, from))
+}
+
+// ExpirePending closes every pending request created before the cutoff
+func (r *Repository) ExpirePending(ctx context.Context, before time.Time) ([]*Request, error) {
+	query := `UPDATE banker_requests
+		SET status = $1, updated_at = $2, closed_at = $2
+		WHERE status = $3 AND created_at < $4
+		RETURNING ` + requestColumns
+
+	rows, err := r.db.Query(ctx, query, StatusExpired, time.Now(), StatusPending, before)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRequests(rows)
+}
+
+// ListRequests returns the most recent requests, optionally filtered by status
+func (r *Repository) ListRequests(ctx context.Context, status Status, limit int) ([]*Request, error) {
+	query := `SELECT ` + requestColumns + ` FROM banker_requests
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRequests(rows)
+}
+
+// ListByStatus returns every request currently in the given status, oldest first
+func (r *Repository) ListByStatus(ctx context.Context, status Status) ([]*Request, error) {
+	query := `SELECT ` + requestColumns + ` FROM banker_requests WHERE status = $1 ORDER BY created_at`
+
+	rows, err := r.db.Query(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRequests(rows)
+}
+
+// Verify marks a completed request as matched against the given funds news entry
+func (r *Repository) Verify(ctx context.Context, id int, newsID string) (*Request, error) {
+	now := time.Now()
+
+	query := `UPDATE banker_requests
+		SET status = $1, verified_news_id = $2, flag_reason = '', updated_at = $3, verified_at = $3
+		WHERE id = $4 AND status = $5
+		RETURNING ` + requestColumns
+
+	return scanRequest(r.db.QueryRow(ctx, query, StatusVerified, newsID, now, id, StatusCompleted))
+}
+
+// Flag records why a completed request could not be verified. It returns
+// ErrRequestNotFound if the request is no longer completed.
+func (r *Repository) Flag(ctx context.Context, id int, reason string) (*Request, error) {
+	query := `UPDATE banker_requests
+		SET flag_reason = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+		RETURNING ` + requestColumns
+
+	return scanRequest(r.db.QueryRow(ctx, query, reason, time.Now(), id, StatusCompleted))
+}
+
+// VerifiedNewsIDs lists funds news entries already matched to a request since the cutoff
+func (r *Repository) VerifiedNewsIDs(ctx context.Context, since time.Time) ([]string, error) {
+	query := `SELECT verified_news_id FROM banker_requests WHERE verified_news_id <> '' AND verified_at >= $1`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func collectRequests(rows pgx.Rows) ([]*Request, error) {
+	defer rows.Close()
+
+	var requests []*Request
+	for rows.Next() {
+		req, err := scanRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
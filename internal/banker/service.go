@@ -0,0 +1,350 @@
+package banker
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"kaizen-hq/config"
+	"kaizen-hq/internal/account"
+	"kaizen-hq/internal/client"
+	"kaizen-hq/internal/user"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// payoutGracePeriod is how long after "Mark sent" a payout may take to show in the news
+	payoutGracePeriod = 30 * time.Minute
+	// payoutClockSkew tolerates payouts logged slightly before the claim was recorded
+	payoutClockSkew = time.Minute
+	// payoutLookback bounds how far back already-matched news IDs are remembered
+	payoutLookback = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid banker request transition")
+	ErrAlreadyClaimed    = errors.New("banker request has already been claimed")
+	ErrNotHandler        = errors.New("only the banker who claimed the request can do that")
+	ErrNotLinked         = errors.New("discord user is not linked to a torn account")
+)
+
+type Service struct {
+	repo           *Repository
+	config         *config.Config
+	accountService *account.Service
+	userService    *user.Service
+	tornClient     client.Client
+}
+
+func NewService(repo *Repository, cfg *config.Config, accountService *account.Service, userService *user.Service, tornClient client.Client) *Service {
+	return &Service{repo: repo, config: cfg, accountService: accountService, userService: userService, tornClient: tornClient}
+}
+
+// LookupRequester resolves a Discord user to their Torn ID and faction vault balance
+func (s *Service) LookupRequester(ctx context.Context, discordID string) (*Requester, error) {
+	player, err := s.userService.GetUserByDiscordID(ctx, discordID)
+	if err != nil {
+		if errors.Is(err, user.ErrProfileNotFound) {
+			return nil, ErrNotLinked
+		}
+		return nil, err
+	}
+
+	balance, err := s.tornClient.FetchFactionBalance(ctx, client.PooledKey, player.PlayerID)
+	if err != nil {
+		if errors.Is(err, client.ErrMemberNotInFaction) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to fetch faction balance: %w", err)
+	}
+
+	return &Requester{TornID: player.PlayerID, Balance: balance}, nil
+}
+
+// CreateRequest persists a new pending banker request
+func (s *Service) CreateRequest(ctx context.Context, guildID, discordID, name string, tornID int, amount int64) (*Request, error) {
+	req := &Request{
+		GuildID:            guildID,
+		RequesterDiscordID: discordID,
+		RequesterName:      name,
+		RequesterTornID:    tornID,
+		Amount:             amount,
+		Status:             StatusPending,
+	}
+
+	if err := s.repo.CreateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (s *Service) GetRequest(ctx context.Context, id int) (*Request, error) {
+	return s.repo.GetRequestByID(ctx, id)
+}
+
+// ListRequests returns the latest requests, optionally only those in the given status
+func (s *Service) ListRequests(ctx context.Context, status Status, limit int) ([]*Request, error) {
+	return s.repo.ListRequests(ctx, status, limit)
+}
+
+func (s *Service) SetAdminMessage(ctx context.Context, id int, channelID, messageID string) error {
+	return s.repo.SetAdminMessage(ctx, id, channelID, messageID)
+}
+
+// Claim assigns a pending request to the banker who pressed the button
+func (s *Service) Claim(ctx context.Context, id int, handlerID, handlerName string) (*Request, error) {
+	req, err := s.repo.GetRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status == StatusClaimed {
+		return nil, ErrAlreadyClaimed
+	}
+	if !req.Status.CanTransitionTo(StatusClaimed) {
+		return nil, invalidTransition(req.Status, StatusClaimed)
+	}
+
+	claimed, err := s.repo.Claim(ctx, id, handlerID, handlerName)
+	if errors.Is(err, ErrRequestNotFound) {
+		// Someone else changed the request between our read and the update
+		return nil, ErrAlreadyClaimed
+	}
+
+	return claimed, err
+}
+
+// Complete marks a claimed request as paid out. Only the claiming banker can complete it.
+func (s *Service) Complete(ctx context.Context, id int, handlerID, handlerName string) (*Request, error) {
+	return s.closeClaimed(ctx, id, StatusCompleted, handlerID, handlerName)
+}
+
+// Fail marks a claimed request as not paid out. Only the claiming banker can fail it.
+func (s *Service) Fail(ctx context.Context, id int, handlerID, handlerName string) (*Request, error) {
+	return s.closeClaimed(ctx, id, StatusFailed, handlerID, handlerName)
+}
+
+// Cancel closes a pending or claimed request without paying it out
+func (s *Service) Cancel(ctx context.Context, id int, actorID, actorName string) (*Request, error) {
+	req, err := s.repo.GetRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status.IsFinal() {
+		return nil, fmt.Errorf("%w: the request is already %s", ErrInvalidTransition, req.Status)
+	}
+
+	return s.close(ctx, req, StatusCancelled, actorID, actorName)
+}
+
+// ExpireStale expires pending requests nobody claimed within maxAge
+func (s *Service) ExpireStale(ctx context.Context, maxAge time.Duration) ([]*Request, error) {
+	return s.repo.ExpirePending(ctx, time.Now().Add(-maxAge))
+}
+
+// VerifyPayouts matches completed requests against the faction funds news.
+// Requests with a matching "was given" entry are marked verified; requests
+// whose payout went to someone else, was the wrong amount, or never showed
+// up within the grace period are flagged once for leadership to review.
+func (s *Service) VerifyPayouts(ctx context.Context) (*VerificationResult, error) {
+	result := &VerificationResult{}
+
+	completed, err := s.repo.ListByStatus(ctx, StatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(completed) == 0 {
+		return result, nil
+	}
+
+	payouts, err := s.fetchPayouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usedIDs, err := s.repo.VerifiedNewsIDs(ctx, time.Now().Add(-payoutLookback))
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(usedIDs))
+	for _, id := range usedIDs {
+		used[id] = true
+	}
+
+	for _, req := range completed {
+		since := req.CreatedAt
+		if req.ClaimedAt != nil {
+			since = *req.ClaimedAt
+		}
+		since = since.Add(-payoutClockSkew)
+
+		var toRequester []Payout
+		for _, p := range payouts {
+			if used[p.NewsID] || p.Time.Before(since) || p.RecipientID != req.RequesterTornID {
+				continue
+			}
+			toRequester = append(toRequester, p)
+		}
+
+		// An exact match verifies the request
+		if i := slices.IndexFunc(toRequester, func(p Payout) bool { return p.Amount == req.Amount }); i >= 0 {
+			verified, err := s.repo.Verify(ctx, req.ID, toRequester[i].NewsID)
+			if err != nil {
+				return nil, err
+			}
+			used[toRequester[i].NewsID] = true
+			result.Verified = append(result.Verified, verified)
+			continue
+		}
+
+		reason := s.mismatchReason(ctx, req, since, toRequester, payouts, used)
+		if reason == "" || reason == req.FlagReason {
+			continue
+		}
+
+		flagged, err := s.repo.Flag(ctx, req.ID, reason)
+		if errors.Is(err, ErrRequestNotFound) {
+			// The request left the completed status since it was listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Flagged = append(result.Flagged, Flag{Request: flagged, Reason: reason})
+	}
+
+	return result, nil
+}
+
+// mismatchReason explains why a completed request has no exact payout match,
+// or returns an empty string if it is still within the grace period
+func (s *Service) mismatchReason(ctx context.Context, req *Request, since time.Time, toRequester, payouts []Payout, used map[string]bool) string {
+	if p, ok := closestPayout(toRequester, req.Amount); ok {
+		return fmt.Sprintf("amount mismatch: requested $%d but $%d was given", req.Amount, p.Amount)
+	}
+
+	// Look for the right amount going to someone else from the handling banker
+	if handler, err := s.userService.GetUserByDiscordID(ctx, req.HandlerDiscordID); err == nil {
+		for _, p := range payouts {
+			if used[p.NewsID] || p.Time.Before(since) {
+				continue
+			}
+			if p.SenderID == handler.PlayerID && p.Amount == req.Amount {
+				return fmt.Sprintf("recipient mismatch: $%d was given to [%d] instead of [%d]", p.Amount, p.RecipientID, req.RequesterTornID)
+			}
+		}
+	}
+
+	if req.ClosedAt != nil && time.Since(*req.ClosedAt) > payoutGracePeriod {
+		return "no matching payout found in faction news"
+	}
+
+	return ""
+}
+
+// closestPayout picks the payout whose amount is nearest the requested one
+func closestPayout(payouts []Payout, amount int64) (Payout, bool) {
+	if len(payouts) == 0 {
+		return Payout{}, false
+	}
+
+	return slices.MinFunc(payouts, func(a, b Payout) int {
+		return cmp.Compare(absDiff(a.Amount, amount), absDiff(b.Amount, amount))
+	}), true
+}
+
+func absDiff(a, b int64) int64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// fetchPayouts reads the funds news with a pooled faction key
+func (s *Service) fetchPayouts(ctx context.Context) ([]Payout, error) {
+	news, err := s.tornClient.FetchFundsNews(ctx, client.PooledKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch funds news: %w", err)
+	}
+
+	var payouts []Payout
+	for id, entry := range news {
+		if p, ok := ParsePayout(id, entry); ok {
+			payouts = append(payouts, p)
+		}
+	}
+
+	// Oldest first so each request is matched to the earliest qualifying payout
+	slices.SortFunc(payouts, func(a, b Payout) int { return a.Time.Compare(b.Time) })
+
+	return payouts, nil
+}
+
+// payoutPattern matches funds news such as
+// <a href="...XID=1">Alice</a> was given $1,000,000 by <a href="...XID=2">Bob</a>
+var payoutPattern = regexp.MustCompile(`XID=(\d+)[^>]*>[^<]*</a> was given \$([\d,]+) by <a[^>]*XID=(\d+)`)
+
+// ParsePayout extracts a money transfer from a funds news entry
+func ParsePayout(newsID string, entry client.NewsEntry) (Payout, bool) {
+	matches := payoutPattern.FindStringSubmatch(entry.News)
+	if len(matches) != 4 {
+		return Payout{}, false
+	}
+
+	recipientID, err1 := strconv.Atoi(matches[1])
+	amount, err2 := strconv.ParseInt(strings.ReplaceAll(matches[2], ",", ""), 10, 64)
+	senderID, err3 := strconv.Atoi(matches[3])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return Payout{}, false
+	}
+
+	return Payout{
+		NewsID:      newsID,
+		RecipientID: recipientID,
+		SenderID:    senderID,
+		Amount:      amount,
+		Time:        time.Unix(entry.Timestamp, 0),
+	}, true
+}
+
+func (s *Service) closeClaimed(ctx context.Context, id int, to Status, handlerID, handlerName string) (*Request, error) {
+	req, err := s.repo.GetRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status == StatusClaimed && req.HandlerDiscordID != handlerID {
+		return nil, ErrNotHandler
+	}
+
+	return s.close(ctx, req, to, handlerID, handlerName)
+}
+
+func (s *Service) close(ctx context.Context, req *Request, to Status, actorID, actorName string) (*Request, error) {
+	if !req.Status.CanTransitionTo(to) {
+		return nil, invalidTransition(req.Status, to)
+	}
+
+	closed, err := s.repo.Close(ctx, req.ID, req.Status, to, actorID, actorName)
+	if errors.Is(err, ErrRequestNotFound) {
+		// The status moved underneath us; report it against the fresh state
+		latest, getErr := s.repo.GetRequestByID(ctx, req.ID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return nil, invalidTransition(latest.Status, to)
+	}
+
+	return closed, err
+}
+
+func invalidTransition(from, to Status) error {
+	return fmt.Errorf("%w: cannot move a %s request to %s", ErrInvalidTransition, from, to)
+}
@@ -0,0 +1,82 @@
+package banker
+
+import (
+	"kaizen-hq/internal/client"
+	"testing"
+	"time"
+)
+
+func TestParsePayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		news   string
+		want   Payout
+		wantOK bool
+	}{
+		{
+			name:   "payout",
+			news:   `<a href="http://www.torn.com/profiles.php?XID=111">Alice</a> was given $1,250,000 by <a href="http://www.torn.com/profiles.php?XID=222">Bob</a>`,
+			want:   Payout{NewsID: "n1", RecipientID: 111, SenderID: 222, Amount: 1_250_000, Time: time.Unix(1700000000, 0)},
+			wantOK: true,
+		},
+		{
+			name:   "small amount",
+			news:   `<a href = "profiles.php?XID=5">Carol</a> was given $900 by <a href = "profiles.php?XID=6">Dave</a>`,
+			want:   Payout{NewsID: "n1", RecipientID: 5, SenderID: 6, Amount: 900, Time: time.Unix(1700000000, 0)},
+			wantOK: true,
+		},
+		{
+			name: "deposit",
+			news: `<a href="profiles.php?XID=111">Alice</a> deposited $1,000`,
+		},
+		{
+			name: "stripped tags",
+			news: `Alice was given $1,000 by Bob`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParsePayout("n1", client.NewsEntry{News: tt.news, Timestamp: 1700000000})
+			if ok != tt.wantOK {
+				t.Fatalf("ParsePayout() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("ParsePayout() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestPayout(t *testing.T) {
+	payouts := []Payout{
+		{NewsID: "a", Amount: 100_000},
+		{NewsID: "b", Amount: 950_000},
+		{NewsID: "c", Amount: 2_000_000},
+	}
+
+	tests := []struct {
+		name    string
+		payouts []Payout
+		amount  int64
+		want    string
+		wantOK  bool
+	}{
+		{name: "none", payouts: nil, amount: 1_000_000},
+		{name: "nearest below", payouts: payouts, amount: 1_000_000, want: "b", wantOK: true},
+		{name: "nearest above", payouts: payouts, amount: 1_800_000, want: "c", wantOK: true},
+		{name: "first on a tie", payouts: payouts, amount: 525_000, want: "a", wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := closestPayout(tt.payouts, tt.amount)
+			if ok != tt.wantOK {
+				t.Fatalf("closestPayout() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.NewsID != tt.want {
+				t.Errorf("closestPayout() = %s, want %s", got.NewsID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,582 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaizen-hq/internal/banker"
+	"kaizen-hq/internal/client"
+	"kaizen-hq/internal/guild"
+	"kaizen-hq/internal/permission"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Button actions available on a banker request in the admin channel
+const (
+	bankerActionClaim    = "claim"
+	bankerActionComplete = "complete"
+	bankerActionFail     = "fail"
+	bankerActionCancel   = "cancel"
+)
+
+// Embed colours for each banker request status
+var bankerStatusColors = map[banker.Status]int{
+	banker.StatusPending:   0x00BFFF, // Blue
+	banker.StatusClaimed:   0xFFAA00, // Orange
+	banker.StatusCompleted: 0x00FF00, // Green
+	banker.StatusFailed:    0xFF0000, // Red
+	banker.StatusCancelled: 0xFF0000, // Red
+	banker.StatusExpired:   0x808080, // Grey
+	banker.StatusVerified:  0x008000, // Dark green
+}
+
+// bankerCommand lets members request money from their faction vault balance
+type bankerCommand struct {
+	bot *Bot
+}
+
+func (c *bankerCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "banker",
+		Description: "Requests banker for the amount",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "amount",
+				Description: "Amount to withdraw (5k, 1.5m, max, half, etc.)",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (c *bankerCommand) Feature() string { return guild.FeatureBanker }
+
+func (c *bankerCommand) Permission() string { return "" }
+
+// Handle processes the /banker command
+func (c *bankerCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Extract the amount string
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	amountStr := optionMap["amount"].StringValue()
+
+	adminChannelID := c.bot.guildSettings(i.GuildID).BankerChannelID
+	if adminChannelID == "" {
+		respondEphemeral(s, i, "Banker requests aren't set up on this server yet. Ask an admin to run `/config banker-channel`.")
+		return
+	}
+
+	// Looking up the balance may queue for a free API key, which can outlast
+	// Discord's window for an initial response
+	if err := deferEphemeral(s, i); err != nil {
+		log.Printf("Error deferring banker response: %v", err)
+		return
+	}
+
+	// Relative amounts like "max" or "half" are resolved against the vault balance
+	requester, err := c.bot.services.Banker.LookupRequester(ctx, i.Member.User.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, banker.ErrNotLinked):
+			editResponse(s, i, "I couldn't find a Torn account linked to your Discord. Please run `/verify me` first.")
+		case errors.Is(err, client.ErrMemberNotInFaction):
+			editResponse(s, i, "You don't appear to have a balance with the faction.")
+		default:
+			log.Printf("Error fetching faction balance: %v", err)
+			editResponse(s, i, "Sorry, I couldn't check your faction balance right now. Please try again later.")
+		}
+		return
+	}
+
+	balance := requester.Balance
+
+	// Parse the amount
+	amount, err := parseAmount(amountStr, balance)
+	if err != nil {
+		editResponse(s, i, fmt.Sprintf("Error: %s", err.Error()))
+		return
+	}
+
+	if amount <= 0 {
+		editResponse(s, i, "Error: the amount must be greater than zero")
+		return
+	}
+
+	if amount > balance {
+		editResponse(s, i, fmt.Sprintf("Error: you requested $%s but your faction balance is only $%s", formatMoney(amount), formatMoney(balance)))
+		return
+	}
+
+	// Persist the request so it survives restarts and lost messages
+	req, err := c.bot.services.Banker.CreateRequest(ctx, i.GuildID, i.Member.User.ID, i.Member.User.Username, requester.TornID, amount)
+	if err != nil {
+		log.Printf("Error creating banker request: %v", err)
+		editResponse(s, i, "Sorry, I couldn't record your request. Please try again in a moment.")
+		return
+	}
+
+	// Send ephemeral response to the requesting user
+	editResponse(s, i, fmt.Sprintf("Got it! Your request for $%s is in the system now. Please give it about 15 minutes to process before making another one. I’m here if you need anything else!", formatMoney(amount)))
+
+	// Send request to admin channel with buttons
+	msg, err := s.ChannelMessageSendComplex(adminChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{bankerAdminEmbed(req)},
+		Components: bankerAdminComponents(req),
+	})
+	if err != nil {
+		log.Printf("Error sending message to admin channel: %v", err)
+		return
+	}
+
+	if err := c.bot.services.Banker.SetAdminMessage(ctx, req.ID, msg.ChannelID, msg.ID); err != nil {
+		log.Printf("Error recording admin message for banker request %d: %v", req.ID, err)
+	}
+}
+
+// handleBankerButton processes button clicks on banker requests in the admin channel
+func (b *Bot) handleBankerButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+
+	action, requestID, ok := parseBankerCustomID(i.MessageComponentData().CustomID)
+	if !ok {
+		return
+	}
+
+	// Only bankers may act on requests, whoever can see the admin channel
+	if !b.authorize(ctx, s, i, permission.BankerFulfill) {
+		return
+	}
+
+	actorID := i.Member.User.ID
+	actorName := i.Member.User.Username
+
+	var (
+		req *banker.Request
+		err error
+	)
+	switch action {
+	case bankerActionClaim:
+		req, err = b.services.Banker.Claim(ctx, requestID, actorID, actorName)
+	case bankerActionComplete:
+		req, err = b.services.Banker.Complete(ctx, requestID, actorID, actorName)
+	case bankerActionFail:
+		req, err = b.services.Banker.Fail(ctx, requestID, actorID, actorName)
+	case bankerActionCancel:
+		req, err = b.services.Banker.Cancel(ctx, requestID, actorID, actorName)
+	default:
+		return
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, banker.ErrInvalidTransition),
+			errors.Is(err, banker.ErrAlreadyClaimed),
+			errors.Is(err, banker.ErrNotHandler),
+			errors.Is(err, banker.ErrRequestNotFound):
+			respondEphemeral(s, i, fmt.Sprintf("Request %d can't be updated: %s", requestID, err.Error()))
+		default:
+			log.Printf("Error updating banker request %d: %v", requestID, err)
+			respondEphemeral(s, i, "Something went wrong while updating this request.")
+		}
+		return
+	}
+
+	// Update the admin message in place to reflect the new state
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{bankerAdminEmbed(req)},
+			Components: bankerAdminComponents(req),
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating banker request message: %v", err)
+	}
+
+	b.notifyBankerRequester(s, req)
+}
+
+// ExpireBankerRequests expires pending requests nobody claimed in time
+// and updates their admin messages and requesters accordingly
+func (b *Bot) ExpireBankerRequests(ctx context.Context) {
+	expired, err := b.services.Banker.ExpireStale(ctx, BankerRequestTTL)
+	if err != nil {
+		log.Printf("Error expiring banker requests: %v", err)
+		return
+	}
+
+	for _, req := range expired {
+		b.refreshBankerAdminMessage(req)
+		b.notifyBankerRequester(b.session, req)
+	}
+}
+
+// VerifyBankerPayouts checks completed requests against the faction funds news,
+// updating verified requests and raising mismatches in the admin channel
+func (b *Bot) VerifyBankerPayouts(ctx context.Context) {
+	result, err := b.services.Banker.VerifyPayouts(ctx)
+	if err != nil {
+		log.Printf("Error verifying banker payouts: %v", err)
+		return
+	}
+
+	for _, req := range result.Verified {
+		b.refreshBankerAdminMessage(req)
+	}
+
+	for _, flag := range result.Flagged {
+		b.refreshBankerAdminMessage(flag.Request)
+
+		channelID := flag.Request.AdminChannelID
+		if channelID == "" {
+			channelID = b.guildSettings(flag.Request.GuildID).BankerChannelID
+		}
+		if channelID == "" {
+			continue
+		}
+
+		msg := &discordgo.MessageSend{
+			Content: fmt.Sprintf("⚠️ Banker request %d handled by <@%s> needs review: %s",
+				flag.Request.ID, flag.Request.HandlerDiscordID, flag.Reason),
+		}
+		if flag.Request.AdminMessageID != "" {
+			msg.Reference = &discordgo.MessageReference{
+				ChannelID: channelID,
+				MessageID: flag.Request.AdminMessageID,
+			}
+		}
+
+		if _, err := b.session.ChannelMessageSendComplex(channelID, msg); err != nil {
+			log.Printf("Error flagging banker request %d: %v", flag.Request.ID, err)
+		}
+
+		b.logToGuild(flag.Request.GuildID, fmt.Sprintf("Banker request %d was flagged: %s", flag.Request.ID, flag.Reason))
+	}
+}
+
+// refreshBankerAdminMessage re-renders the stored admin message for a request
+func (b *Bot) refreshBankerAdminMessage(req *banker.Request) {
+	if req.AdminChannelID == "" || req.AdminMessageID == "" {
+		return
+	}
+
+	components := bankerAdminComponents(req)
+	_, err := b.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    req.AdminChannelID,
+		ID:         req.AdminMessageID,
+		Embeds:     &[]*discordgo.MessageEmbed{bankerAdminEmbed(req)},
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error updating admin message for banker request %d: %v", req.ID, err)
+	}
+}
+
+// notifyBankerRequester DMs the requester about the current state of their request
+func (b *Bot) notifyBankerRequester(s *discordgo.Session, req *banker.Request) {
+	channel, err := s.UserChannelCreate(req.RequesterDiscordID)
+	if err != nil {
+		log.Printf("Error creating DM channel: %v", err)
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color: bankerStatusColors[req.Status],
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Amount",
+				Value:  "$" + formatMoney(req.Amount),
+				Inline: true,
+			},
+			{
+				Name:   "Status",
+				Value:  bankerStatusLabel(req.Status),
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Request ID: %d", req.ID),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	switch req.Status {
+	case banker.StatusClaimed:
+		embed.Title = "Your Banker Request is Being Processed"
+		embed.Description = "Hey there! A banker has picked up your request and will send the money shortly."
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Handler",
+			Value:  fmt.Sprintf("<@%s>", req.HandlerDiscordID),
+			Inline: true,
+		})
+		embed.Footer.Text += " - We'll notify you once it's done!"
+	case banker.StatusCompleted:
+		embed.Title = "Banker Request - Completed"
+		embed.Description = "Your money has been sent. Check your Torn events!"
+	case banker.StatusFailed:
+		embed.Title = "Banker Request - Failed"
+		embed.Description = "Unfortunately, your banker request could not be completed. Please contact a banker or try again later."
+	case banker.StatusCancelled:
+		embed.Title = "Banker Request - Cancelled"
+		embed.Description = "Your banker request has been cancelled"
+	case banker.StatusExpired:
+		embed.Title = "Banker Request - Expired"
+		embed.Description = "No banker was available to pick up your request in time. Feel free to make a new one."
+	default:
+		return
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(channel.ID, embed); err != nil {
+		log.Printf("Error sending DM: %v", err)
+	}
+}
+
+// bankerAdminEmbed renders the admin channel view of a request from its stored state
+func bankerAdminEmbed(req *banker.Request) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Banker Request - " + bankerStatusLabel(req.Status),
+		Description: "A user has requested a banker withdrawal",
+		Color:       bankerStatusColors[req.Status],
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "User",
+				Value:  fmt.Sprintf("<@%s> (%s)", req.RequesterDiscordID, req.RequesterName),
+				Inline: true,
+			},
+			{
+				Name:   "Amount",
+				Value:  formatMoney(req.Amount),
+				Inline: true,
+			},
+			{
+				Name:   "Request Time",
+				Value:  req.CreatedAt.Format(time.RFC1123),
+				Inline: false,
+			},
+			{
+				Name:   "Status",
+				Value:  bankerStatusLabel(req.Status),
+				Inline: false,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Request ID: %d", req.ID),
+		},
+	}
+
+	if req.Status == banker.StatusPending {
+		embed.Title = "New Banker Request"
+	}
+
+	if req.HandlerDiscordID != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Handled By",
+			Value:  req.HandlerName,
+			Inline: true,
+		})
+	}
+
+	if req.ClosedByDiscordID != "" && req.ClosedByDiscordID != req.HandlerDiscordID {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Closed By",
+			Value:  req.ClosedByName,
+			Inline: true,
+		})
+	}
+
+	if req.ClosedAt != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Closed At",
+			Value:  req.ClosedAt.Format(time.RFC1123),
+			Inline: false,
+		})
+	}
+
+	if req.VerifiedAt != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Verified At",
+			Value:  req.VerifiedAt.Format(time.RFC1123),
+			Inline: false,
+		})
+	}
+
+	if req.FlagReason != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "⚠️ Needs Review",
+			Value:  req.FlagReason,
+			Inline: false,
+		})
+	}
+
+	return embed
+}
+
+// bankerAdminComponents returns the buttons valid for the request's current status
+func bankerAdminComponents(req *banker.Request) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+
+	switch req.Status {
+	case banker.StatusPending:
+		buttons = []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Fulfill",
+				Style:    discordgo.SuccessButton,
+				CustomID: bankerCustomID(bankerActionClaim, req.ID),
+			},
+			discordgo.Button{
+				Label:    "Cancel",
+				Style:    discordgo.DangerButton,
+				CustomID: bankerCustomID(bankerActionCancel, req.ID),
+			},
+		}
+	case banker.StatusClaimed:
+		buttons = []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Mark sent",
+				Style:    discordgo.SuccessButton,
+				CustomID: bankerCustomID(bankerActionComplete, req.ID),
+			},
+			discordgo.Button{
+				Label:    "Failed",
+				Style:    discordgo.DangerButton,
+				CustomID: bankerCustomID(bankerActionFail, req.ID),
+			},
+			discordgo.Button{
+				Label:    "Cancel",
+				Style:    discordgo.SecondaryButton,
+				CustomID: bankerCustomID(bankerActionCancel, req.ID),
+			},
+		}
+	default:
+		// Closed requests have no actions left
+		return []discordgo.MessageComponent{}
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: buttons},
+	}
+}
+
+func bankerStatusLabel(status banker.Status) string {
+	switch status {
+	case banker.StatusPending:
+		return "Pending"
+	case banker.StatusClaimed:
+		return "In Progress"
+	case banker.StatusCompleted:
+		return "Completed"
+	case banker.StatusFailed:
+		return "Failed"
+	case banker.StatusCancelled:
+		return "Cancelled"
+	case banker.StatusExpired:
+		return "Expired"
+	case banker.StatusVerified:
+		return "Verified"
+	}
+	return string(status)
+}
+
+// bankerCustomID builds the button ID for an action on a stored banker request
+func bankerCustomID(action string, requestID int) string {
+	return fmt.Sprintf("banker:%s:%d", action, requestID)
+}
+
+// parseBankerCustomID extracts the action and request ID from a banker button ID
+func parseBankerCustomID(customID string) (string, int, bool) {
+	rest, ok := strings.CutPrefix(customID, "banker:")
+	if !ok {
+		return "", 0, false
+	}
+
+	action, idStr, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", 0, false
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return action, id, true
+}
+
+// parseAmount converts strings like "5k", "1.5m", "half", etc. into integer values
+func parseAmount(input string, maxValue int64) (int64, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	// Handle special text cases
+	switch input {
+	case "max":
+		return maxValue, nil
+	case "half", "1/2", "50%":
+		return int64(math.Floor(float64(maxValue) * 0.5)), nil
+	case "quarter", "1/4", "25%":
+		return int64(math.Floor(float64(maxValue) * 0.25)), nil
+	case "1/3", "33%":
+		return int64(math.Floor(float64(maxValue) * (1.0 / 3.0))), nil
+	}
+
+	// Handle percentage cases
+	if strings.HasSuffix(input, "%") {
+		percentStr := strings.TrimSuffix(input, "%")
+		percent, err := strconv.ParseFloat(percentStr, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage format: %s", input)
+		}
+		return int64(math.Floor(float64(maxValue) * (percent / 100.0))), nil
+	}
+
+	// Handle fraction cases with regex
+	fractionRegex := regexp.MustCompile(`^(\d+)/(\d+)$`)
+	matches := fractionRegex.FindStringSubmatch(input)
+	if len(matches) == 3 {
+		numerator, err1 := strconv.ParseFloat(matches[1], 64)
+		denominator, err2 := strconv.ParseFloat(matches[2], 64)
+		if err1 != nil || err2 != nil || denominator == 0 {
+			return 0, fmt.Errorf("invalid fraction format: %s", input)
+		}
+		return int64(math.Floor(float64(maxValue) * (numerator / denominator))), nil
+	}
+
+	// Handle numeric values with suffixes (k, m, b)
+	numRegex := regexp.MustCompile(`^([-+]?\d*\.?\d+)([kmb])?$`)
+	matches = numRegex.FindStringSubmatch(input)
+	if len(matches) >= 2 {
+		base, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number format: %s", input)
+		}
+
+		// Apply multiplier if suffix exists
+		if len(matches) == 3 && matches[2] != "" {
+			switch matches[2] {
+			case "k":
+				base *= 1000
+			case "m":
+				base *= 1000000
+			case "b":
+				base *= 1000000000
+			}
+		}
+
+		// Convert to integer (floor)
+		return int64(math.Floor(base)), nil
+	}
+
+	// If we get here, the format wasn't recognized
+	return 0, fmt.Errorf("unrecognized amount format: %s", input)
+}
@@ -0,0 +1,47 @@
+package bot
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	const balance = 1_000_000
+
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "max", want: 1_000_000},
+		{input: " MAX ", want: 1_000_000},
+		{input: "half", want: 500_000},
+		{input: "quarter", want: 250_000},
+		{input: "1/3", want: 333_333},
+		{input: "10%", want: 100_000},
+		{input: "12.5%", want: 125_000},
+		{input: "3/4", want: 750_000},
+		{input: "250000", want: 250_000},
+		{input: "250k", want: 250_000},
+		{input: "1.5m", want: 1_500_000},
+		{input: "2b", want: 2_000_000_000},
+		{input: "1/0", wantErr: true},
+		{input: "abc%", wantErr: true},
+		{input: "lots", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.input, balance)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAmount(%q) = %d, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseAmount(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAmount(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaizen-hq/internal/account"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Command is a self-contained slash command. The bot registers every command
+// in its registry with Discord and dispatches interactions to it by name.
+type Command interface {
+	// Definition describes the command and its options to Discord
+	Definition() *discordgo.ApplicationCommand
+	// Feature is the guild feature that must be enabled, or "" if the command is always available
+	Feature() string
+	// Permission is the name of the permission a member needs to run the command, or "" for everyone
+	Permission() string
+	// Handle runs the command for an interaction
+	Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate)
+}
+
+// pingCommand replies with pong so members can check the bot is alive
+type pingCommand struct{}
+
+func (c *pingCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "ping",
+		Description: "Replies with pong",
+	}
+}
+
+func (c *pingCommand) Feature() string { return "" }
+
+func (c *pingCommand) Permission() string { return "" }
+
+func (c *pingCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Pong! I'm hana still under development",
+		},
+	})
+}
+
+// registerCommand adds a command to the bot's registry
+func (b *Bot) registerCommand(cmd Command) {
+	b.commands[cmd.Definition().Name] = cmd
+}
+
+// guildCommands returns the definitions of the commands enabled in a guild
+func (b *Bot) guildCommands(guildID string) []*discordgo.ApplicationCommand {
+	settings := b.guildSettings(guildID)
+
+	var definitions []*discordgo.ApplicationCommand
+	for _, cmd := range b.commands {
+		if feature := cmd.Feature(); feature != "" && !settings.FeatureEnabled(feature) {
+			continue
+		}
+
+		definitions = append(definitions, cmd.Definition())
+	}
+
+	return definitions
+}
+
+// authorize checks that the member behind an interaction holds the named
+// permission through the roles on their linked account. When they don't,
+// it replies with an ephemeral message and returns false.
+func (b *Bot) authorize(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, perm string) bool {
+	if perm == "" {
+		return true
+	}
+
+	if i.Member == nil {
+		respondEphemeral(s, i, "This can only be used inside a server.")
+		return false
+	}
+
+	acc, err := b.services.Account.GetAccountByDiscordID(ctx, i.Member.User.ID)
+	if err != nil {
+		if !errors.Is(err, account.ErrUserNotFound) {
+			log.Printf("Error loading account for %s: %v", i.Member.User.ID, err)
+		}
+		respondEphemeral(s, i, fmt.Sprintf("You need the `%s` permission to do that.", perm))
+		return false
+	}
+
+	ok, err := b.services.Permission.AccountHasPermission(ctx, acc.ID, perm)
+	if err != nil {
+		log.Printf("Error checking permission %s for account %d: %v", perm, acc.ID, err)
+		respondEphemeral(s, i, "Something went wrong while checking your permissions.")
+		return false
+	}
+
+	if !ok {
+		respondEphemeral(s, i, fmt.Sprintf("You need the `%s` permission to do that.", perm))
+		return false
+	}
+
+	return true
+}
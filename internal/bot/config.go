@@ -0,0 +1,247 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"kaizen-hq/internal/guild"
+	"kaizen-hq/internal/permission"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// configCommand lets server admins configure the bot for their guild
+type configCommand struct {
+	bot *Bot
+}
+
+func (c *configCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "config",
+		Description: "Configure the bot for this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show the current configuration",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "banker-channel",
+				Description: "Set the channel where banker requests are posted",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Banker admin channel",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						Required:     true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "log-channel",
+				Description: "Set the channel where the bot logs important events",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Log channel",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						Required:     true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "verified-role",
+				Description: "Set the role given to verified members",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Verified role",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "feature",
+				Description: "Enable or disable a feature",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Feature name",
+						Required:    true,
+						Choices:     featureChoices(),
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Whether the feature is enabled",
+						Required:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (c *configCommand) Feature() string { return "" }
+
+func (c *configCommand) Permission() string { return permission.ConfigEdit }
+
+func featureChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(guild.AllFeatures))
+	for _, feature := range guild.AllFeatures {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: feature, Value: feature})
+	}
+	return choices
+}
+
+// Handle processes the /config command
+func (c *configCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(sub.Options))
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+
+	var (
+		settings *guild.Settings
+		err      error
+		change   string
+	)
+	switch sub.Name {
+	case "show":
+		respondEphemeral(s, i, describeSettings(c.bot.guildSettings(i.GuildID)))
+		return
+	case "banker-channel":
+		channel := options["channel"].ChannelValue(nil)
+		settings, err = c.bot.services.Guild.SetBankerChannel(ctx, i.GuildID, channel.ID)
+		change = fmt.Sprintf("banker channel set to <#%s>", channel.ID)
+	case "log-channel":
+		channel := options["channel"].ChannelValue(nil)
+		settings, err = c.bot.services.Guild.SetLogChannel(ctx, i.GuildID, channel.ID)
+		change = fmt.Sprintf("log channel set to <#%s>", channel.ID)
+	case "verified-role":
+		role := options["role"].RoleValue(nil, i.GuildID)
+		settings, err = c.bot.services.Guild.SetVerifiedRole(ctx, i.GuildID, role.ID)
+		change = fmt.Sprintf("verified role set to <@&%s>", role.ID)
+	case "feature":
+		feature := options["name"].StringValue()
+		enabled := options["enabled"].BoolValue()
+		settings, err = c.bot.services.Guild.SetFeature(ctx, i.GuildID, feature, enabled)
+		change = fmt.Sprintf("feature `%s` disabled", feature)
+		if enabled {
+			change = fmt.Sprintf("feature `%s` enabled", feature)
+		}
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Printf("Error updating settings for guild %s: %v", i.GuildID, err)
+		respondEphemeral(s, i, fmt.Sprintf("Error: %s", err.Error()))
+		return
+	}
+
+	c.bot.storeSettings(settings)
+
+	// Feature toggles change which commands the guild should see
+	if sub.Name == "feature" {
+		if err := c.bot.registerCommands(i.GuildID); err != nil {
+			log.Printf("Error re-registering commands: %v", err)
+		}
+	}
+
+	respondEphemeral(s, i, "Updated: "+change)
+	c.bot.logToGuild(i.GuildID, fmt.Sprintf("<@%s> updated the configuration: %s", i.Member.User.ID, change))
+}
+
+// describeSettings renders a guild's settings for the /config show command
+func describeSettings(settings *guild.Settings) string {
+	orUnset := func(value, format string) string {
+		if value == "" {
+			return "not set"
+		}
+		return fmt.Sprintf(format, value)
+	}
+
+	features := "none"
+	if len(settings.EnabledFeatures) > 0 {
+		features = strings.Join(settings.EnabledFeatures, ", ")
+	}
+
+	return fmt.Sprintf("**Banker channel:** %s\n**Log channel:** %s\n**Verified role:** %s\n**Enabled features:** %s",
+		orUnset(settings.BankerChannelID, "<#%s>"),
+		orUnset(settings.LogChannelID, "<#%s>"),
+		orUnset(settings.VerifiedRoleID, "<@&%s>"),
+		features,
+	)
+}
+
+// handleGuildCreate loads the guild's settings and registers its commands
+// whenever the bot connects to or joins a guild
+func (b *Bot) handleGuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	settings, err := b.services.Guild.GetSettings(context.Background(), g.ID)
+	if err != nil {
+		log.Printf("Error loading settings for guild '%s': %v", g.Name, err)
+		settings = guild.DefaultSettings(g.ID)
+	}
+	b.storeSettings(settings)
+
+	if err := b.registerCommands(g.ID); err != nil {
+		log.Println(err)
+	}
+}
+
+// loadSettings fills the settings cache from the database
+func (b *Bot) loadSettings(ctx context.Context) error {
+	list, err := b.services.Guild.ListSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, settings := range list {
+		b.storeSettings(settings)
+	}
+
+	log.Printf("Loaded settings for %d guilds", len(list))
+	return nil
+}
+
+func (b *Bot) storeSettings(settings *guild.Settings) {
+	b.settingsMu.Lock()
+	defer b.settingsMu.Unlock()
+
+	b.settings[settings.GuildID] = settings
+}
+
+// guildSettings returns the cached settings for a guild, falling back to the defaults
+func (b *Bot) guildSettings(guildID string) *guild.Settings {
+	b.settingsMu.RLock()
+	defer b.settingsMu.RUnlock()
+
+	if settings, ok := b.settings[guildID]; ok {
+		return settings
+	}
+
+	return guild.DefaultSettings(guildID)
+}
+
+// logToGuild posts a message to the guild's log channel, if one is configured
+func (b *Bot) logToGuild(guildID, content string) {
+	channelID := b.guildSettings(guildID).LogChannelID
+	if channelID == "" {
+		return
+	}
+
+	if _, err := b.session.ChannelMessageSend(channelID, content); err != nil {
+		log.Printf("Error writing to log channel of guild %s: %v", guildID, err)
+	}
+}
@@ -0,0 +1,205 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"kaizen-hq/internal/faction"
+	"kaizen-hq/internal/guild"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// gymTopTrainers is how many members the /gym leaderboard lists
+const gymTopTrainers = 10
+
+// gymCommand shows who trained the most gym energy over a period
+type gymCommand struct {
+	bot *Bot
+}
+
+func (c *gymCommand) Definition() *discordgo.ApplicationCommand {
+	periods := []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "day", Value: faction.PeriodDay},
+		{Name: "week", Value: faction.PeriodWeek},
+		{Name: "month", Value: faction.PeriodMonth},
+	}
+
+	stats := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(faction.GymStats))
+	for _, stat := range faction.GymStats {
+		stats = append(stats, &discordgo.ApplicationCommandOptionChoice{Name: stat, Value: stat})
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        "gym",
+		Description: "Shows the faction's top gym trainers and who hasn't trained",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "period",
+				Description: "Period to rank over (defaults to week)",
+				Choices:     periods,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "stat",
+				Description: "Stat to rank by (defaults to total)",
+				Choices:     stats,
+			},
+		},
+	}
+}
+
+func (c *gymCommand) Feature() string { return guild.FeatureFaction }
+
+func (c *gymCommand) Permission() string { return "" }
+
+// Handle processes the /gym command
+func (c *gymCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	period, stat := faction.PeriodWeek, faction.StatTotal
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "period":
+			period = opt.StringValue()
+		case "stat":
+			stat = opt.StringValue()
+		}
+	}
+
+	to := time.Now()
+	from, _ := faction.PeriodStart(period, to)
+
+	board, err := c.bot.services.Faction.GymLeaderboard(ctx, from, to, stat)
+	if err != nil {
+		log.Printf("Error loading gym leaderboard: %v", err)
+		respondEphemeral(s, i, "Sorry, I couldn't load the gym leaderboard right now.")
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{gymEmbed(board, period)},
+		},
+	})
+	if err != nil {
+		log.Printf("Error sending gym leaderboard: %v", err)
+	}
+}
+
+// gymEmbed renders the top trainers of a leaderboard and the members who trained nothing
+func gymEmbed(board *faction.GymLeaderboard, period string) *discordgo.MessageEmbed {
+	var top []string
+	for n, e := range board.Entries {
+		if n == gymTopTrainers || e.Stat(board.Stat) == 0 {
+			break
+		}
+		top = append(top, fmt.Sprintf("%d. %s — %s", n+1, gymMemberName(e), formatMoney(int64(e.Stat(board.Stat)))))
+	}
+
+	var idle []string
+	for _, e := range board.Idle() {
+		idle = append(idle, gymMemberName(e))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("Gym Leaderboard — %s over the last %s", board.Stat, period),
+		Color: 0x800080,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Top Trainers", Value: embedList(top)},
+			{Name: fmt.Sprintf("No Energy Trained (%d)", len(idle)), Value: embedList(idle)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s to %s", board.From.Format("Jan 2 15:04"), board.To.Format("Jan 2 15:04 MST")),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// gymMemberName shows a member as "Name [ID]", or just the ID if their name isn't known
+func gymMemberName(g faction.GymGain) string {
+	if g.Name == "" {
+		return fmt.Sprintf("[%d]", g.TornID)
+	}
+	return fmt.Sprintf("%s [%d]", g.Name, g.TornID)
+}
+
+// EvaluateGymQuotas checks everyone's gym energy over the past week against
+// their quota, DMs the members who fell short and posts a summary to the log
+// channel of every guild with the faction feature enabled
+func (b *Bot) EvaluateGymQuotas(ctx context.Context) error {
+	to := time.Now()
+	evaluation, err := b.services.Faction.EvaluateGymQuotas(ctx, to.Add(-faction.QuotaPeriod), to)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range evaluation.Below() {
+		b.notifyBelowQuota(ctx, result)
+	}
+
+	summary := gymQuotaEmbed(evaluation)
+	for _, g := range b.session.State.Guilds {
+		settings := b.guildSettings(g.ID)
+		if settings.LogChannelID == "" || !settings.FeatureEnabled(guild.FeatureFaction) {
+			continue
+		}
+		if _, err := b.session.ChannelMessageSendEmbed(settings.LogChannelID, summary); err != nil {
+			log.Printf("Error posting gym quota summary to guild %s: %v", g.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// notifyBelowQuota DMs a member who trained less than their quota, if their Discord account is known
+func (b *Bot) notifyBelowQuota(ctx context.Context, result faction.QuotaResult) {
+	player, err := b.services.User.GetUserByPlayerID(ctx, result.TornID)
+	if err != nil || player.DiscordID == "" {
+		return
+	}
+
+	channel, err := b.session.UserChannelCreate(player.DiscordID)
+	if err != nil {
+		log.Printf("Error creating DM channel: %v", err)
+		return
+	}
+
+	content := fmt.Sprintf(
+		"You trained %s gym energy this week, short of the faction's requirement of %s. "+
+			"If you're going to be away, ask leadership to record a leave of absence.",
+		formatMoney(int64(result.Total)), formatMoney(int64(result.Required)),
+	)
+	if _, err := b.session.ChannelMessageSend(channel.ID, content); err != nil {
+		log.Printf("Error sending gym quota reminder to %s: %v", player.DiscordID, err)
+	}
+}
+
+// gymQuotaEmbed summarises a quota evaluation for leadership
+func gymQuotaEmbed(evaluation *faction.QuotaEvaluation) *discordgo.MessageEmbed {
+	var below []string
+	for _, r := range evaluation.Below() {
+		below = append(below, fmt.Sprintf("%s — %s / %s",
+			gymMemberName(r.GymGain), formatMoney(int64(r.Total)), formatMoney(int64(r.Required))))
+	}
+
+	var exempt []string
+	for _, r := range evaluation.Exempt() {
+		exempt = append(exempt, gymMemberName(r.GymGain))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Weekly Gym Quota Report",
+		Description: fmt.Sprintf("**%d** of **%d** members met their quota.", len(evaluation.Results)-len(below), len(evaluation.Results)),
+		Color:       0x800080,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: fmt.Sprintf("Below Quota (%d)", len(below)), Value: embedList(below)},
+			{Name: fmt.Sprintf("On Leave (%d)", len(exempt)), Value: embedList(exempt)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s to %s", evaluation.From.Format("Jan 2 15:04"), evaluation.To.Format("Jan 2 15:04 MST")),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
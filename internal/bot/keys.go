@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"kaizen-hq/internal/client"
+	"log"
+)
+
+// HandleInvalidKey stops using an API key Torn reported as incorrect or paused
+// and tells each owner by DM that they need to replace or resume it
+func (b *Bot) HandleInvalidKey(ctx context.Context, apiKey string, cause error) {
+	accounts, err := b.services.Account.InvalidateAPIKey(ctx, apiKey)
+	if err != nil {
+		log.Printf("Error invalidating API key: %v", err)
+		return
+	}
+
+	for _, acc := range accounts {
+		log.Printf("Marked the API key of account %d invalid: %v", acc.ID, cause)
+		if acc.DiscordID == "" {
+			continue
+		}
+
+		channel, err := b.session.UserChannelCreate(acc.DiscordID)
+		if err != nil {
+			log.Printf("Error creating DM channel: %v", err)
+			continue
+		}
+
+		if _, err := b.session.ChannelMessageSend(channel.ID, invalidKeyMessage(cause)); err != nil {
+			log.Printf("Error sending invalid key notice to %s: %v", acc.DiscordID, err)
+		}
+	}
+}
+
+// invalidKeyMessage explains to a key's owner why it stopped being used
+func invalidKeyMessage(cause error) string {
+	if errors.Is(cause, client.ErrKeyPaused) {
+		return "Torn reports the API key stored with your account is paused, so it is no longer being used. " +
+			"Resume it in your Torn settings and store it again from your account page to restore access."
+	}
+	return "Torn reports the API key stored with your account is incorrect, so it is no longer being used. " +
+		"Create a new key with limited or full access and store it from your account page to restore access."
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"kaizen-hq/internal/faction"
+	"kaizen-hq/internal/guild"
+	"kaizen-hq/internal/permission"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// membersEventDays is the period /members reports joins and departures for
+const membersEventDays = 7
+
+// membersCommand summarises the faction roster and recent membership changes
+type membersCommand struct {
+	bot *Bot
+}
+
+func (c *membersCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "members",
+		Description: "Shows the faction roster and who joined or left this week",
+	}
+}
+
+func (c *membersCommand) Feature() string { return guild.FeatureFaction }
+
+func (c *membersCommand) Permission() string { return permission.ViewLogs }
+
+// Handle processes the /members command
+func (c *membersCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	members, err := c.bot.services.Faction.ListMembers(ctx)
+	if err != nil {
+		log.Printf("Error loading faction members: %v", err)
+		respondEphemeral(s, i, "Sorry, I couldn't load the faction roster right now.")
+		return
+	}
+
+	events, err := c.bot.services.Faction.MemberEvents(ctx, time.Now().AddDate(0, 0, -membersEventDays))
+	if err != nil {
+		log.Printf("Error loading faction member events: %v", err)
+		respondEphemeral(s, i, "Sorry, I couldn't load the faction roster right now.")
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{membersEmbed(members, events)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error sending members summary: %v", err)
+	}
+}
+
+// membersEmbed renders the roster size and the joins and departures of the period
+func membersEmbed(members []faction.Member, events []faction.MemberEvent) *discordgo.MessageEmbed {
+	var joined, left []string
+	for _, e := range events {
+		line := fmt.Sprintf("%s [%d] <t:%d:R>", e.Name, e.TornID, e.CreatedAt.Unix())
+		if e.Type == faction.MemberJoined {
+			joined = append(joined, line)
+		} else {
+			left = append(left, line)
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Faction Roster",
+		Description: fmt.Sprintf("**Members:** %d", len(members)),
+		Color:       0x800080,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: fmt.Sprintf("Joined (%d)", len(joined)), Value: embedList(joined)},
+			{Name: fmt.Sprintf("Left (%d)", len(left)), Value: embedList(left)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Last %d days", membersEventDays),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// embedList joins lines for an embed field, trimming to Discord's 1024 character limit
+func embedList(lines []string) string {
+	if len(lines) == 0 {
+		return "Nobody"
+	}
+
+	var b strings.Builder
+	for n, line := range lines {
+		if b.Len()+len(line)+1 > 1000 {
+			fmt.Fprintf(&b, "…and %d more", len(lines)-n)
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaizen-hq/internal/client"
+	"kaizen-hq/internal/faction"
+	"kaizen-hq/internal/guild"
+	"kaizen-hq/internal/user"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// profileGymDays is the period gym energy stats on /profile are averaged over
+const profileGymDays = 30
+
+// profileCommand shows a member's stored Torn profile
+type profileCommand struct {
+	bot *Bot
+}
+
+func (c *profileCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "profile",
+		Description: "Displays user data",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "member",
+				Description: "Member to show (defaults to you)",
+				Required:    false,
+			},
+		},
+	}
+}
+
+func (c *profileCommand) Feature() string { return guild.FeatureProfile }
+
+func (c *profileCommand) Permission() string { return "" }
+
+// Handle processes the /profile command
+func (c *profileCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	target := i.Member.User
+	if options := i.ApplicationCommandData().Options; len(options) > 0 {
+		target = options[0].UserValue(s)
+	}
+
+	// A stale profile is refreshed from Torn, which may take a moment
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring profile response: %v", err)
+		return
+	}
+
+	userProfile, err := c.bot.services.User.GetProfile(ctx, target.ID)
+	if err != nil {
+		content := "Sorry, I couldn't load that profile right now."
+		if errors.Is(err, user.ErrProfileNotFound) {
+			content = fmt.Sprintf("<@%s> hasn't linked their Torn account yet. They can do so with `/verify me`.", target.ID)
+		} else {
+			log.Printf("Error loading profile for %s: %v", target.ID, err)
+		}
+		editResponse(s, i, content)
+		return
+	}
+
+	embed := profileEmbed(userProfile)
+
+	gym, err := c.bot.services.Faction.GymSummary(ctx, userProfile.PlayerID, profileGymDays)
+	switch {
+	case err == nil:
+		embed.Description += fmt.Sprintf("**Gym Energy:** %s/day (%s over %d days)\n",
+			formatMoney(int64(gym.PerDay())), formatMoney(int64(gym.Total)), gym.Days)
+	case errors.Is(err, faction.ErrNoGymSnapshots):
+		embed.Description += "**Gym Energy:** no data yet\n"
+	default:
+		log.Printf("Error loading gym summary for %d: %v", userProfile.PlayerID, err)
+	}
+
+	embed.Description += c.spiedStats(ctx, i.Member.User.ID, userProfile.PlayerID)
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		log.Printf("Error sending profile: %v", err)
+	}
+}
+
+// spiedStats describes the latest TornStats spy on a player that the viewer can
+// see with their stored API key, or nothing if there isn't one
+func (c *profileCommand) spiedStats(ctx context.Context, viewerID string, tornID int) string {
+	if c.bot.services.TornStats == nil {
+		return ""
+	}
+
+	viewer, err := c.bot.services.Account.GetAccountByDiscordID(ctx, viewerID)
+	if err != nil || viewer.APIKey == "" {
+		return ""
+	}
+
+	spy, err := c.bot.services.TornStats.FetchSpy(ctx, viewer.APIKey, tornID)
+	if err != nil {
+		// TornStats reports viewers who aren't registered and players nobody spied as errors
+		var providerErr *client.ProviderError
+		if !errors.As(err, &providerErr) {
+			log.Printf("Error fetching TornStats spy on %d: %v", tornID, err)
+		}
+		return ""
+	}
+
+	return fmt.Sprintf("**Battle Stats:** %s (STR %s, DEF %s, SPD %s, DEX %s), spied <t:%d:R>\n",
+		formatMoney(spy.Total), formatMoney(spy.Strength), formatMoney(spy.Defense),
+		formatMoney(spy.Speed), formatMoney(spy.Dexterity), spy.Timestamp)
+}
+
+// profileEmbed renders the stored profile information of a player
+func profileEmbed(userProfile *user.User) *discordgo.MessageEmbed {
+	donatorStatus := "False"
+	if userProfile.Donator != 0 {
+		donatorStatus = "True"
+	}
+
+	activity := "unknown"
+	if !userProfile.LastActionAt.IsZero() {
+		activity = fmt.Sprintf("%s, last action <t:%d:R>", userProfile.LastActionStatus, userProfile.LastActionAt.Unix())
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: userProfile.Rank,
+		Description: "**" + userProfile.Name + "** (ID: " + strconv.Itoa(userProfile.PlayerID) + ")\n\n" +
+			"**Level:** " + strconv.Itoa(userProfile.Level) + "\n" +
+			"**Awards:** " + strconv.Itoa(userProfile.Awards) + "\n" +
+			"**Friends:** " + strconv.Itoa(userProfile.Friends) + "\n" +
+			"**Enemies:** " + strconv.Itoa(userProfile.Enemies) + "\n" +
+			"**Age:** " + strconv.Itoa(userProfile.Age) + " days\n" +
+			"**Property:** " + userProfile.Property + "\n" +
+			"**Donator Status:** " + donatorStatus + "\n" +
+			"---\n" +
+			"**Activity:** " + activity + "\n",
+		Color: 0x800080,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: userProfile.ProfileImage,
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Profile as of " + userProfile.UpdatedAt.Format("January 2, 2006 15:04 MST"),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaizen-hq/internal/client"
+	"kaizen-hq/internal/guild"
+	"kaizen-hq/internal/permission"
+	"kaizen-hq/internal/user"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// verifyAllDelay spaces out Torn lookups during /verify all to stay under the API rate limit
+const verifyAllDelay = 1 * time.Second
+
+// verifyCommand links Discord members to their Torn accounts
+type verifyCommand struct {
+	bot *Bot
+}
+
+func (c *verifyCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "verify",
+		Description: "Link Discord members to their Torn accounts",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "me",
+				Description: "Verify your own Torn account",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "all",
+				Description: "Verify every member of this server (requires verify.all)",
+			},
+		},
+	}
+}
+
+func (c *verifyCommand) Feature() string { return guild.FeatureVerify }
+
+// Permission is checked per subcommand, since anyone may verify themselves
+func (c *verifyCommand) Permission() string { return "" }
+
+// Handle processes the /verify command
+func (c *verifyCommand) Handle(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.ApplicationCommandData().Options[0].Name {
+	case "me":
+		c.verifyMe(ctx, s, i)
+	case "all":
+		if !c.bot.authorize(ctx, s, i, permission.VerifyAll) {
+			return
+		}
+		c.verifyAll(s, i)
+	}
+}
+
+func (c *verifyCommand) verifyMe(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Torn lookups can take longer than Discord's 3 second response window
+	if err := deferEphemeral(s, i); err != nil {
+		log.Printf("Error deferring verify response: %v", err)
+		return
+	}
+
+	player, err := c.verifyMember(ctx, i.GuildID, i.Member)
+
+	var content string
+	switch {
+	case err == nil:
+		content = fmt.Sprintf("You're verified as **%s [%d]**.", player.Name, player.PlayerID)
+	case errors.Is(err, client.ErrDiscordNotLinked):
+		content = "Your Discord account isn't linked on Torn yet. Link it from your Torn preferences and try again."
+	default:
+		log.Printf("Error verifying %s: %v", i.Member.User.ID, err)
+		content = "Sorry, I couldn't verify you right now. Please try again later."
+	}
+
+	editResponse(s, i, content)
+}
+
+func (c *verifyCommand) verifyAll(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if err := deferEphemeral(s, i); err != nil {
+		log.Printf("Error deferring verify response: %v", err)
+		return
+	}
+
+	// Walking the whole member list takes a while, so report back when done
+	go func() {
+		// The interaction's lifetime doesn't bound this background walk
+		ctx := context.Background()
+
+		var verified, notLinked, failed int
+		after := ""
+		for {
+			members, err := s.GuildMembers(i.GuildID, after, 1000)
+			if err != nil {
+				log.Printf("Error listing members of guild %s: %v", i.GuildID, err)
+				break
+			}
+
+			for _, member := range members {
+				if member.User.Bot {
+					continue
+				}
+
+				_, err := c.verifyMember(ctx, i.GuildID, member)
+				switch {
+				case err == nil:
+					verified++
+				case errors.Is(err, client.ErrDiscordNotLinked):
+					notLinked++
+				default:
+					log.Printf("Error verifying %s: %v", member.User.ID, err)
+					failed++
+				}
+
+				time.Sleep(verifyAllDelay)
+			}
+
+			if len(members) < 1000 {
+				break
+			}
+			after = members[len(members)-1].User.ID
+		}
+
+		summary := fmt.Sprintf("Verification finished: %d verified, %d not linked on Torn, %d failed.", verified, notLinked, failed)
+		if _, err := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+			Content: summary,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}); err != nil {
+			log.Printf("Error sending verify summary: %v", err)
+		}
+		c.bot.logToGuild(i.GuildID, fmt.Sprintf("<@%s> ran /verify all. %s", i.Member.User.ID, summary))
+	}()
+
+	editResponse(s, i, "Verifying every member of the server. I'll report back when it's done.")
+}
+
+// verifyMember links a member to their Torn account, renames them to
+// "Name [ID]" and gives them the guild's verified role
+func (c *verifyCommand) verifyMember(ctx context.Context, guildID string, member *discordgo.Member) (*user.User, error) {
+	player, err := c.bot.services.User.VerifyDiscord(ctx, member.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	nickname := fmt.Sprintf("%s [%d]", player.Name, player.PlayerID)
+	if member.Nick != nickname {
+		// Discord refuses to rename the server owner or members above the bot
+		if err := c.bot.session.GuildMemberNickname(guildID, member.User.ID, nickname); err != nil {
+			log.Printf("Error renaming %s: %v", member.User.ID, err)
+		}
+	}
+
+	roleID := c.bot.guildSettings(guildID).VerifiedRoleID
+	if roleID != "" && !slices.Contains(member.Roles, roleID) {
+		if err := c.bot.session.GuildMemberRoleAdd(guildID, member.User.ID, roleID); err != nil {
+			log.Printf("Error adding verified role to %s: %v", member.User.ID, err)
+		}
+	}
+
+	return player, nil
+}
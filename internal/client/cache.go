@@ -0,0 +1,376 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Selections the cache keeps responses for
+const (
+	SelectionProfile      = "profile"
+	SelectionDiscord      = "discord"
+	SelectionContributors = "contributors"
+	SelectionDonations    = "donations"
+	SelectionFundsNews    = "fundsnews"
+	SelectionPositions    = "positions"
+	SelectionBasic        = "basic"
+	SelectionMembers      = "members"
+	SelectionCrimes       = "crimes"
+)
+
+// DefaultTTLs are how long each selection is cached unless configured otherwise.
+// Vault balances aren't cached since the banker needs them exact.
+var DefaultTTLs = map[string]time.Duration{
+	SelectionProfile:      30 * time.Second,
+	SelectionDiscord:      10 * time.Minute,
+	SelectionContributors: 5 * time.Minute,
+	SelectionDonations:    0,
+	SelectionFundsNews:    30 * time.Second,
+	SelectionPositions:    10 * time.Minute,
+	SelectionBasic:        time.Minute,
+	SelectionMembers:      time.Minute,
+	SelectionCrimes:       5 * time.Minute,
+}
+
+// sharedFetchTimeout bounds a fetch shared between callers, which may have to
+// queue for a pooled key, since no single caller's context does
+const sharedFetchTimeout = time.Minute
+
+// maxCacheEntries is the size at which the in-memory cache sweeps out expired entries
+const maxCacheEntries = 1024
+
+// CacheStore persists cached responses, such as in Postgres, so they survive restarts
+type CacheStore interface {
+	Get(ctx context.Context, key string) (value []byte, expiresAt time.Time, err error)
+	Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// CacheOption allows configuring the cache with functional options
+type CacheOption func(*Cache)
+
+// WithTTL sets how long responses for a selection are cached, zero disabling caching
+func WithTTL(selection string, ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttls[selection] = ttl
+	}
+}
+
+// WithCacheStore keeps cached responses in the store as well as in memory
+func WithCacheStore(store CacheStore) CacheOption {
+	return func(c *Cache) {
+		c.store = store
+	}
+}
+
+// SelectionStats counts how requests for a selection were served
+type SelectionStats struct {
+	Hits      int64 `json:"hits"`
+	StoreHits int64 `json:"store_hits"`
+	Misses    int64 `json:"misses"`
+	Shared    int64 `json:"shared"`
+}
+
+// CacheStats describes the cache's contents and how often it has been useful
+type CacheStats struct {
+	Entries    int                       `json:"entries"`
+	Selections map[string]SelectionStats `json:"selections"`
+}
+
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Cache is a Client that reuses recent Torn responses for a TTL set per
+// selection, and lets concurrent requests for the same data share one call.
+// Cached values are shared between callers, so they must not be modified.
+type Cache struct {
+	Client
+	ttls  map[string]time.Duration
+	store CacheStore
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	stats   map[string]*SelectionStats
+}
+
+// NewCache wraps a client with a response cache
+func NewCache(inner Client, opts ...CacheOption) *Cache {
+	cache := &Cache{
+		Client:  inner,
+		ttls:    map[string]time.Duration{},
+		entries: map[string]cacheEntry{},
+		stats:   map[string]*SelectionStats{},
+	}
+
+	for selection, ttl := range DefaultTTLs {
+		cache.ttls[selection] = ttl
+	}
+
+	for _, opt := range opts {
+		opt(cache)
+	}
+
+	return cache
+}
+
+// Stats returns the cache's hit and miss counts for each selection
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{Entries: len(c.entries), Selections: map[string]SelectionStats{}}
+	for selection, s := range c.stats {
+		stats.Selections[selection] = *s
+	}
+	return stats
+}
+
+// PurgeExpired drops expired responses from memory and the store
+func (c *Cache) PurgeExpired(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	for key, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if c.store == nil {
+		return nil
+	}
+	_, err := c.store.PurgeExpired(ctx)
+	return err
+}
+
+// count records how a request for the selection was served
+func (c *Cache) count(selection string, record func(*SelectionStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.stats[selection]
+	if !ok {
+		s = &SelectionStats{}
+		c.stats[selection] = s
+	}
+	record(s)
+}
+
+// lookup returns an unexpired in-memory entry
+func (c *Cache) lookup(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// remember keeps a value in memory until it expires
+func (c *Cache) remember(key string, value any, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCacheEntries {
+		now := time.Now()
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expiresAt: expiresAt}
+}
+
+// cacheKey identifies a response by the API version it was fetched with,
+// since v1 and v2 may answer the same selection differently
+func (c *Cache) cacheKey(ctx context.Context, selection, key string) string {
+	version := APIVersion(ctx, c.Client)
+	if version == DefaultVersion {
+		version = "v1"
+	}
+	return version + ":" + selection + ":" + key
+}
+
+// keyScope is the part of a cache key naming whose view of the faction a
+// response is. Pooled keys all belong to the one faction; a caller's own key
+// may not, so it is scoped by a hash of the key rather than the key itself.
+func keyScope(apiKey string) string {
+	if apiKey == PooledKey {
+		return "pooled"
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key/" + hex.EncodeToString(sum[:8])
+}
+
+// cached serves the selection from memory or the store while it is fresh,
+// otherwise fetching it once for every caller waiting on the same key. The
+// shared fetch outlives any one caller giving up, so it runs detached from
+// their cancellation under its own timeout.
+func cached[T any](ctx context.Context, c *Cache, selection, key string, fetch func(context.Context) (T, error)) (T, error) {
+	ttl := c.ttls[selection]
+	if ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	key = c.cacheKey(ctx, selection, key)
+	if value, ok := c.lookup(key); ok {
+		c.count(selection, func(s *SelectionStats) { s.Hits++ })
+		return value.(T), nil
+	}
+
+	// singleflight reports the caller that made the request as shared too
+	fetched := false
+	results := c.group.DoChan(key, func() (any, error) {
+		fetched = true
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedFetchTimeout)
+		defer cancel()
+
+		if value, ok := loadStored[T](ctx, c, key); ok {
+			c.count(selection, func(s *SelectionStats) { s.StoreHits++ })
+			return value, nil
+		}
+
+		c.count(selection, func(s *SelectionStats) { s.Misses++ })
+		value, err := fetch(ctx)
+		if err != nil {
+			return value, err
+		}
+
+		expiresAt := time.Now().Add(ttl)
+		c.remember(key, value, expiresAt)
+		saveStored(ctx, c, key, value, expiresAt)
+		return value, nil
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-results:
+		if res.Shared && !fetched {
+			c.count(selection, func(s *SelectionStats) { s.Shared++ })
+		}
+
+		result, _ := res.Val.(T)
+		return result, res.Err
+	}
+}
+
+// loadStored reads an unexpired value from the store, if there is one
+func loadStored[T any](ctx context.Context, c *Cache, key string) (T, bool) {
+	var value T
+	if c.store == nil {
+		return value, false
+	}
+
+	data, expiresAt, err := c.store.Get(ctx, key)
+	if err != nil || data == nil || time.Now().After(expiresAt) {
+		return value, false
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false
+	}
+
+	c.remember(key, value, expiresAt)
+	return value, true
+}
+
+// saveStored writes a fetched value to the store. Failures only lose the
+// persisted copy, so they are logged rather than failing the request.
+func saveStored(ctx context.Context, c *Cache, key string, value any, expiresAt time.Time) {
+	if c.store == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = c.store.Set(ctx, key, data, expiresAt)
+	}
+	if err != nil {
+		log.Printf("Error storing cached %s: %v", key, err)
+	}
+}
+
+func (c *Cache) FetchGymEnergy(ctx context.Context, apiKey, stat string) (StatMap, error) {
+	return cached(ctx, c, SelectionContributors, keyScope(apiKey)+"/"+stat, func(ctx context.Context) (StatMap, error) {
+		return c.Client.FetchGymEnergy(ctx, apiKey, stat)
+	})
+}
+
+// FetchTornUser caches profiles looked up by ID. A key owner's own profile
+// is always fetched since it depends on the key.
+func (c *Cache) FetchTornUser(ctx context.Context, apiKey, tornID string) (*User, error) {
+	if tornID == "" {
+		return c.Client.FetchTornUser(ctx, apiKey, tornID)
+	}
+	return cached(ctx, c, SelectionProfile, tornID, func(ctx context.Context) (*User, error) {
+		return c.Client.FetchTornUser(ctx, apiKey, tornID)
+	})
+}
+
+func (c *Cache) FetchDiscordID(ctx context.Context, apiKey string, tornID int) (string, error) {
+	return cached(ctx, c, SelectionDiscord, strconv.Itoa(tornID), func(ctx context.Context) (string, error) {
+		return c.Client.FetchDiscordID(ctx, apiKey, tornID)
+	})
+}
+
+func (c *Cache) FetchTornIDByDiscordID(ctx context.Context, apiKey, discordID string) (int, error) {
+	return cached(ctx, c, SelectionDiscord, "discord/"+discordID, func(ctx context.Context) (int, error) {
+		return c.Client.FetchTornIDByDiscordID(ctx, apiKey, discordID)
+	})
+}
+
+func (c *Cache) FetchFactionBalance(ctx context.Context, apiKey string, tornID int) (int64, error) {
+	return cached(ctx, c, SelectionDonations, keyScope(apiKey)+"/"+strconv.Itoa(tornID), func(ctx context.Context) (int64, error) {
+		return c.Client.FetchFactionBalance(ctx, apiKey, tornID)
+	})
+}
+
+func (c *Cache) FetchFundsNews(ctx context.Context, apiKey string) (map[string]NewsEntry, error) {
+	return cached(ctx, c, SelectionFundsNews, keyScope(apiKey), func(ctx context.Context) (map[string]NewsEntry, error) {
+		return c.Client.FetchFundsNews(ctx, apiKey)
+	})
+}
+
+func (c *Cache) FetchFactionPositions(ctx context.Context, apiKey string) (map[string]Position, error) {
+	return cached(ctx, c, SelectionPositions, keyScope(apiKey), func(ctx context.Context) (map[string]Position, error) {
+		return c.Client.FetchFactionPositions(ctx, apiKey)
+	})
+}
+
+func (c *Cache) FetchFactionBasic(ctx context.Context, apiKey string) (*FactionBasic, error) {
+	return cached(ctx, c, SelectionBasic, keyScope(apiKey), func(ctx context.Context) (*FactionBasic, error) {
+		return c.Client.FetchFactionBasic(ctx, apiKey)
+	})
+}
+
+func (c *Cache) FetchFactionMembers(ctx context.Context, apiKey string) ([]FactionMemberDetails, error) {
+	return cached(ctx, c, SelectionMembers, keyScope(apiKey), func(ctx context.Context) ([]FactionMemberDetails, error) {
+		return c.Client.FetchFactionMembers(ctx, apiKey)
+	})
+}
+
+func (c *Cache) FetchFactionCrimes(ctx context.Context, apiKey, category string) ([]Crime, error) {
+	return cached(ctx, c, SelectionCrimes, keyScope(apiKey)+"/"+category, func(ctx context.Context) ([]Crime, error) {
+		return c.Client.FetchFactionCrimes(ctx, apiKey, category)
+	})
+}
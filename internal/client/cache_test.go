@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowClient holds every faction basic request until it is released
+type slowClient struct {
+	*fakeClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowClient) FetchFactionBasic(ctx context.Context, apiKey string) (*FactionBasic, error) {
+	s.started <- struct{}{}
+	<-s.release
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.fakeClient.FetchFactionBasic(ctx, apiKey)
+}
+
+func TestCacheTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		expire    bool
+		wantCalls int
+		wantStats SelectionStats
+	}{
+		{
+			name:      "fresh entry is reused",
+			ttl:       time.Minute,
+			wantCalls: 1,
+			wantStats: SelectionStats{Hits: 1, Misses: 1},
+		},
+		{
+			name:      "expired entry is fetched again",
+			ttl:       time.Minute,
+			expire:    true,
+			wantCalls: 2,
+			wantStats: SelectionStats{Misses: 2},
+		},
+		{
+			name:      "zero ttl isn't cached",
+			ttl:       0,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeClient()
+			cache := NewCache(fake, WithTTL(SelectionBasic, tt.ttl))
+			ctx := context.Background()
+
+			if _, err := cache.FetchFactionBasic(ctx, PooledKey); err != nil {
+				t.Fatal(err)
+			}
+			if tt.expire {
+				cache.mu.Lock()
+				for key, e := range cache.entries {
+					e.expiresAt = time.Now().Add(-time.Second)
+					cache.entries[key] = e
+				}
+				cache.mu.Unlock()
+			}
+			if _, err := cache.FetchFactionBasic(ctx, PooledKey); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := fake.callCount(PooledKey); got != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", got, tt.wantCalls)
+			}
+			if got := cache.Stats().Selections[SelectionBasic]; got != tt.wantStats {
+				t.Errorf("stats = %+v, want %+v", got, tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestCacheKeys(t *testing.T) {
+	type call struct {
+		version    string
+		ctxVersion *string
+		apiKey     string
+	}
+	v1, v2 := DefaultVersion, VersionV2
+
+	tests := []struct {
+		name      string
+		calls     []call
+		wantCalls int
+	}{
+		{
+			name:      "same key and version share an entry",
+			calls:     []call{{DefaultVersion, nil, PooledKey}, {DefaultVersion, nil, PooledKey}},
+			wantCalls: 1,
+		},
+		{
+			name:      "explicit key doesn't see the pooled entry",
+			calls:     []call{{DefaultVersion, nil, PooledKey}, {DefaultVersion, nil, "other-faction"}},
+			wantCalls: 2,
+		},
+		{
+			name:      "versions don't share an entry",
+			calls:     []call{{DefaultVersion, nil, PooledKey}, {VersionV2, nil, PooledKey}},
+			wantCalls: 2,
+		},
+		{
+			name:      "a call's own version doesn't share the client version's entry",
+			calls:     []call{{DefaultVersion, nil, PooledKey}, {DefaultVersion, &v2, PooledKey}},
+			wantCalls: 2,
+		},
+		{
+			name:      "a call's own version shares an entry with the same client version",
+			calls:     []call{{VersionV2, nil, PooledKey}, {DefaultVersion, &v2, PooledKey}, {VersionV2, &v1, PooledKey}},
+			wantCalls: 2,
+		},
+		{
+			name:      "the default version is v1",
+			calls:     []call{{DefaultVersion, nil, PooledKey}, {"v1", nil, PooledKey}},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeClient()
+			cache := NewCache(fake)
+
+			for _, c := range tt.calls {
+				fake.mu.Lock()
+				fake.version = c.version
+				fake.mu.Unlock()
+
+				ctx := context.Background()
+				if c.ctxVersion != nil {
+					ctx = WithAPIVersion(ctx, *c.ctxVersion)
+				}
+				if _, err := cache.FetchFactionBasic(ctx, c.apiKey); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			calls := fake.callCount(PooledKey) + fake.callCount("other-faction")
+			if calls != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheSingleflight(t *testing.T) {
+	const callers = 5
+
+	slow := &slowClient{
+		fakeClient: newFakeClient(),
+		started:    make(chan struct{}, callers),
+		release:    make(chan struct{}),
+	}
+	cache := NewCache(slow)
+
+	var wg sync.WaitGroup
+	results := make([]*FactionBasic, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cache.FetchFactionBasic(context.Background(), PooledKey)
+		}()
+	}
+
+	// Let the first request reach Torn, give the others time to queue behind it, then answer
+	<-slow.started
+	time.Sleep(50 * time.Millisecond)
+	close(slow.release)
+	wg.Wait()
+
+	if got := slow.callCount(PooledKey); got != 1 {
+		t.Errorf("made %d requests, want 1", got)
+	}
+	for i, r := range results {
+		if r == nil || r != results[0] {
+			t.Errorf("caller %d got %v, want the shared response", i, r)
+		}
+	}
+
+	stats := cache.Stats().Selections[SelectionBasic]
+	if stats.Misses != 1 || stats.Hits+stats.Shared != callers-1 {
+		t.Errorf("stats = %+v, want 1 miss and %d hits or shared", stats, callers-1)
+	}
+}
+
+func TestCacheSingleflightCancel(t *testing.T) {
+	slow := &slowClient{
+		fakeClient: newFakeClient(),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	cache := NewCache(slow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := cache.FetchFactionBasic(ctx, PooledKey)
+		firstErr <- err
+	}()
+	<-slow.started
+
+	second := make(chan *FactionBasic, 1)
+	go func() {
+		basic, err := cache.FetchFactionBasic(context.Background(), PooledKey)
+		if err != nil {
+			t.Errorf("waiting caller returned error: %v", err)
+		}
+		second <- basic
+	}()
+
+	// The caller that started the request gives up without waiting for Torn
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller returned %v, want %v", err, context.Canceled)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(slow.release)
+	if basic := <-second; basic == nil {
+		t.Error("waiting caller got no response")
+	}
+	if got := slow.callCount(PooledKey); got != 1 {
+		t.Errorf("made %d requests, want 1", got)
+	}
+}
@@ -3,13 +3,23 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 )
 
+var (
+	// ErrMemberNotInFaction is returned when a player is missing from the faction's records
+	ErrMemberNotInFaction = errors.New("player is not a member of the faction")
+	// ErrDiscordNotLinked is returned when a Discord account has no Torn account linked to it
+	ErrDiscordNotLinked = errors.New("discord account is not linked on torn")
+)
+
 // ClientOption allows configuring the torn client with functional options
 type ClientOption func(*client)
 
@@ -46,8 +56,19 @@ type Client interface {
 	FetchGymEnergy(ctx context.Context, apiKey, stat string) (StatMap, error)
 	FetchTornUser(ctx context.Context, apiKey, tornID string) (*User, error)
 	FetchDiscordID(ctx context.Context, apiKey string, tornID int) (string, error)
+	FetchTornIDByDiscordID(ctx context.Context, apiKey, discordID string) (int, error)
 	FetchKeyDetails(ctx context.Context, apiKey string) (int, error)
+	FetchFactionBalance(ctx context.Context, apiKey string, tornID int) (int64, error)
+	FetchFundsNews(ctx context.Context, apiKey string) (map[string]NewsEntry, error)
+	FetchFactionPositions(ctx context.Context, apiKey string) (map[string]Position, error)
+	FetchFactionBasic(ctx context.Context, apiKey string) (*FactionBasic, error)
+
+	// FetchFactionMembers and FetchFactionCrimes read v2 only selections
+	FetchFactionMembers(ctx context.Context, apiKey string) ([]FactionMemberDetails, error)
+	FetchFactionCrimes(ctx context.Context, apiKey, category string) ([]Crime, error)
 
+	// Version returns the API version calls use unless their context picks one
+	Version() string
 	// SwitchVersion changes the API version at runtime
 	SwitchVersion(version string)
 	// SwitchBaseURL changes the base URL at runtime
@@ -65,9 +86,30 @@ const (
 
 	// Default API version
 	DefaultVersion = ""
+	// VersionV2 selects the Torn API v2 endpoints and response shapes
+	VersionV2 = "v2"
 )
 
+// versionKey is the context key under which a per-call API version is stored
+type versionKey struct{}
+
+// WithAPIVersion makes calls made with the returned context use the given
+// API version instead of the client's own
+func WithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, versionKey{}, version)
+}
+
+// APIVersion returns the API version a call made with the context uses on the client
+func APIVersion(ctx context.Context, c Client) string {
+	if version, ok := ctx.Value(versionKey{}).(string); ok {
+		return version
+	}
+	return c.Version()
+}
+
 type client struct {
+	// mu guards baseURL and version, which may be switched while requests are in flight
+	mu      sync.RWMutex
 	baseURL string
 	version string
 	client  *http.Client
@@ -75,7 +117,8 @@ type client struct {
 
 /*
 NewClient creates a new client based on options provided.
-It defaults to use the torn api v1 but can be configured as per need.
+It defaults to use the torn api v1 but can be configured as per need,
+and WithAPIVersion picks the version for a single call.
 */
 func NewClient(opts ...ClientOption) Client {
 	client := &client{
@@ -92,7 +135,8 @@ func NewClient(opts ...ClientOption) Client {
 	return client
 }
 
-// NewClientWithProvider creates a new client with a predefined provider
+// NewClientWithProvider creates a new client with a predefined provider.
+// TornStats and YATA aren't Torn shaped, so use NewTornStatsClient and NewYataClient for them.
 func NewClientWithProvider(provider TornAPIProvider, opts ...ClientOption) Client {
 	// Start with the provider option
 	providerOpt := WithBaseURL(string(provider))
@@ -103,27 +147,48 @@ func NewClientWithProvider(provider TornAPIProvider, opts ...ClientOption) Clien
 	return NewClient(allOpts...)
 }
 
+// Version returns the client's configured API version
+func (t *client) Version() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.version
+}
+
 // SwitchVersion changes the API version at runtime
 func (t *client) SwitchVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.version = version
 }
 
 // SwitchBaseURL changes the base URL at runtime
 func (t *client) SwitchBaseURL(baseURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.baseURL = baseURL
 }
 
+// useV2 reports whether a call should use the v2 API, going by the context
+// first and the client's configured version otherwise
+func (t *client) useV2(ctx context.Context) bool {
+	return APIVersion(ctx, t) == VersionV2
+}
+
 // buildURL constructs the complete API URL (API key is passed dynamically)
-func (t *client) buildURL(apiKey, endpoint, selections string, params map[string]string) (string, error) {
-	base, err := url.Parse(t.baseURL)
+func (t *client) buildURL(version, apiKey, endpoint, selections string, params map[string]string) (string, error) {
+	t.mu.RLock()
+	baseURL := t.baseURL
+	t.mu.RUnlock()
+
+	base, err := url.Parse(baseURL)
 	if err != nil {
 		return "", fmt.Errorf("invalid base URL: %w", err)
 	}
 
 	// Add version if not empty
 	path := "/"
-	if t.version != "" {
-		path += t.version + "/"
+	if version != "" {
+		path += version + "/"
 	}
 	path += endpoint
 
@@ -131,7 +196,9 @@ func (t *client) buildURL(apiKey, endpoint, selections string, params map[string
 
 	// Add query parameters
 	q := base.Query()
-	q.Set("key", apiKey) // API key passed dynamically
+	if apiKey != "" {
+		q.Set("key", apiKey) // API key passed dynamically
+	}
 	if selections != "" {
 		q.Set("selections", selections)
 	}
@@ -167,9 +234,29 @@ func (t *client) makeRequest(ctx context.Context, url string, result any) error
 	return json.NewDecoder(res.Body).Decode(result)
 }
 
+// requestInto requests the URL and decodes the body into each target in turn,
+// for responses that carry an error alongside the data
+func (t *client) requestInto(ctx context.Context, url string, targets ...any) error {
+	var body json.RawMessage
+	if err := t.makeRequest(ctx, url, &body); err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if err := json.Unmarshal(body, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *client) FetchGymEnergy(ctx context.Context, apiKey, stat string) (StatMap, error) {
+	if t.useV2(ctx) {
+		return t.fetchGymEnergyV2(ctx, apiKey, stat)
+	}
+
 	params := map[string]string{"stat": stat}
-	url, err := t.buildURL(apiKey, "faction", "contributors", params)
+	url, err := t.buildURL(DefaultVersion, apiKey, "faction", "contributors", params)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +278,11 @@ func (t *client) FetchGymEnergy(ctx context.Context, apiKey, stat string) (StatM
 }
 
 func (t *client) FetchTornUser(ctx context.Context, apiKey, tornID string) (*User, error) {
-	url, err := t.buildURL(apiKey, fmt.Sprintf("user/%s", tornID), "profile", nil)
+	if t.useV2(ctx) {
+		return t.fetchTornUserV2(ctx, apiKey, tornID)
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, fmt.Sprintf("user/%s", tornID), "profile", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +324,15 @@ func (t *client) FetchTornUser(ctx context.Context, apiKey, tornID string) (*Use
 }
 
 func (t *client) FetchDiscordID(ctx context.Context, apiKey string, tornID int) (string, error) {
-	url, err := t.buildURL(apiKey, fmt.Sprintf("user/%d", tornID), "discord", nil)
+	if t.useV2(ctx) {
+		discord, err := t.fetchDiscordV2(ctx, apiKey, strconv.Itoa(tornID))
+		if err != nil {
+			return "", err
+		}
+		return discord.DiscordID, nil
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, fmt.Sprintf("user/%d", tornID), "discord", nil)
 	if err != nil {
 		return "", err
 	}
@@ -254,28 +353,178 @@ func (t *client) FetchDiscordID(ctx context.Context, apiKey string, tornID int)
 	return parsed.Discord.DiscordID, nil
 }
 
+// FetchTornIDByDiscordID resolves the Torn player linked to a Discord account
+func (t *client) FetchTornIDByDiscordID(ctx context.Context, apiKey, discordID string) (int, error) {
+	if t.useV2(ctx) {
+		discord, err := t.fetchDiscordV2(ctx, apiKey, discordID)
+		if err != nil {
+			return 0, err
+		}
+		if discord.UserID == 0 {
+			return 0, ErrDiscordNotLinked
+		}
+		return discord.UserID, nil
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, fmt.Sprintf("user/%s", discordID), "discord", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var parsed struct {
+		Discord Discord   `json:"discord"`
+		Error   *APIError `json:"error"`
+	}
+
+	if err := t.makeRequest(ctx, url, &parsed); err != nil {
+		return 0, err
+	}
+
+	if parsed.Error != nil {
+		return 0, parsed.Error
+	}
+
+	if parsed.Discord.UserID == 0 {
+		return 0, ErrDiscordNotLinked
+	}
+
+	return parsed.Discord.UserID, nil
+}
+
 func (t *client) FetchKeyDetails(ctx context.Context, apiKey string) (int, error) {
-	url, err := t.buildURL(apiKey, "key", "info", nil)
+	if t.useV2(ctx) {
+		return t.fetchKeyDetailsV2(ctx, apiKey)
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, "key", "info", nil)
 	if err != nil {
 		return 0, err
 	}
 
 	var key Key
+	var parsed struct {
+		Error *APIError `json:"error"`
+	}
 
-	if err := t.makeRequest(ctx, url, &key); err != nil {
+	if err := t.requestInto(ctx, url, &parsed, &key); err != nil {
 		return 0, err
 	}
 
+	if parsed.Error != nil {
+		return 0, parsed.Error
+	}
+
 	return key.AccessLevel, nil
 }
 
-// APIError represents an error from the Torn API
-type APIError struct {
-	Code    int    `json:"code"`
-	Message string `json:"error"`
+// FetchFactionBalance returns the money a member holds in the faction vault.
+// The API key must belong to someone with faction API access.
+func (t *client) FetchFactionBalance(ctx context.Context, apiKey string, tornID int) (int64, error) {
+	if t.useV2(ctx) {
+		return t.fetchFactionBalanceV2(ctx, apiKey, tornID)
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, "faction", "donations", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var parsed struct {
+		Donations map[string]Donation `json:"donations"`
+		Error     *APIError           `json:"error"`
+	}
+
+	if err := t.makeRequest(ctx, url, &parsed); err != nil {
+		return 0, err
+	}
+
+	if parsed.Error != nil {
+		return 0, parsed.Error
+	}
+
+	donation, ok := parsed.Donations[strconv.Itoa(tornID)]
+	if !ok {
+		return 0, ErrMemberNotInFaction
+	}
+
+	return donation.MoneyBalance, nil
+}
+
+// FetchFundsNews returns the faction's recent money and points movements keyed by news ID
+func (t *client) FetchFundsNews(ctx context.Context, apiKey string) (map[string]NewsEntry, error) {
+	if t.useV2(ctx) {
+		return t.fetchFundsNewsV2(ctx, apiKey)
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, "faction", "fundsnews", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		FundsNews map[string]NewsEntry `json:"fundsnews"`
+		Error     *APIError            `json:"error"`
+	}
+
+	if err := t.makeRequest(ctx, url, &parsed); err != nil {
+		return nil, err
+	}
+
+	if parsed.Error != nil {
+		return nil, parsed.Error
+	}
+
+	return parsed.FundsNews, nil
+}
+
+// FetchFactionPositions returns the faction's custom positions keyed by name.
+// The built in Leader, Co-leader and Recruit positions are not included.
+// Positions are always read from v1, whose permission flags the role mapping is built on.
+func (t *client) FetchFactionPositions(ctx context.Context, apiKey string) (map[string]Position, error) {
+	url, err := t.buildURL(DefaultVersion, apiKey, "faction", "positions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Positions map[string]Position `json:"positions"`
+		Error     *APIError           `json:"error"`
+	}
+
+	if err := t.makeRequest(ctx, url, &parsed); err != nil {
+		return nil, err
+	}
+
+	if parsed.Error != nil {
+		return nil, parsed.Error
+	}
+
+	return parsed.Positions, nil
 }
 
-// Error implements the error interface
-func (e *APIError) Error() string {
-	return fmt.Sprintf("Torn API error %d: %s", e.Code, e.Message)
+// FetchFactionBasic returns the faction's basic information and member list
+func (t *client) FetchFactionBasic(ctx context.Context, apiKey string) (*FactionBasic, error) {
+	if t.useV2(ctx) {
+		return t.fetchFactionBasicV2(ctx, apiKey)
+	}
+
+	url, err := t.buildURL(DefaultVersion, apiKey, "faction", "basic", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		FactionBasic
+		Error *APIError `json:"error"`
+	}
+
+	if err := t.makeRequest(ctx, url, &parsed); err != nil {
+		return nil, err
+	}
+
+	if parsed.Error != nil {
+		return nil, parsed.Error
+	}
+
+	return &parsed.FactionBasic, nil
 }
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestAPIVersionSelection(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{"access_level": 3, "info": {"access": {"level": 3}}}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		clientVersion string
+		ctxVersion    *string
+		wantPath      string
+	}{
+		{name: "default", wantPath: "/key"},
+		{name: "configured v2", clientVersion: VersionV2, wantPath: "/v2/key/info"},
+		{name: "call picks v2", ctxVersion: ptr(VersionV2), wantPath: "/v2/key/info"},
+		{name: "call picks v1 over configured v2", clientVersion: VersionV2, ctxVersion: ptr(DefaultVersion), wantPath: "/key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			paths = nil
+			mu.Unlock()
+
+			c := NewClient(WithBaseURL(srv.URL), WithVersion(tt.clientVersion))
+			ctx := context.Background()
+			if tt.ctxVersion != nil {
+				ctx = WithAPIVersion(ctx, *tt.ctxVersion)
+			}
+
+			level, err := c.FetchKeyDetails(ctx, "key")
+			if err != nil {
+				t.Fatalf("FetchKeyDetails() returned error: %v", err)
+			}
+			if level != AccessLimited {
+				t.Errorf("FetchKeyDetails() = %d, want %d", level, AccessLimited)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(paths) != 1 || paths[0] != tt.wantPath {
+				t.Errorf("requested %v, want [%s]", paths, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestSwitchVersionWhileRequesting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_level": 1, "info": {"access": {"level": 1}}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				c.SwitchVersion(VersionV2)
+			} else {
+				c.SwitchVersion(DefaultVersion)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := c.FetchKeyDetails(context.Background(), "key"); err != nil {
+				t.Errorf("FetchKeyDetails() returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Torn API errors, matched against an APIError with errors.Is
+var (
+	ErrIncorrectKey     = errors.New("torn api key is incorrect")
+	ErrTooManyRequests  = errors.New("torn api key made too many requests")
+	ErrIPBlocked        = errors.New("torn api has temporarily blocked this ip")
+	ErrAPIDisabled      = errors.New("torn api is temporarily disabled")
+	ErrKeyOwnerInactive = errors.New("torn api key is disabled due to owner inactivity")
+	ErrAccessLevel      = errors.New("torn api key access level is too low")
+	ErrKeyPaused        = errors.New("torn api key is paused by its owner")
+)
+
+// codeErrors maps Torn error codes to their sentinel errors
+var codeErrors = map[int]error{
+	2:  ErrIncorrectKey,
+	5:  ErrTooManyRequests,
+	8:  ErrIPBlocked,
+	9:  ErrAPIDisabled,
+	13: ErrKeyOwnerInactive,
+	16: ErrAccessLevel,
+	18: ErrKeyPaused,
+}
+
+// keyErrorCodes are the Torn error codes caused by the key itself, where another key may succeed:
+// incorrect key, wrong entity relation, owner in jail, inactive owner, access level too low and paused key
+var keyErrorCodes = []int{2, 7, 10, 13, 16, 18}
+
+// transientCodes are the Torn error codes worth retrying after a pause:
+// too many requests, IP block and API disabled
+var transientCodes = []int{5, 8, 9}
+
+// APIError represents an error from the Torn API
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"error"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Torn API error %d: %s", e.Code, e.Message)
+}
+
+// Is lets errors.Is match an APIError against the sentinel for its code
+func (e *APIError) Is(target error) bool {
+	sentinel, ok := codeErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// IsKeyError reports whether err is a Torn API error caused by the key that was used
+func IsKeyError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && slices.Contains(keyErrorCodes, apiErr.Code)
+}
+
+// IsTransient reports whether err is a Torn API error that may succeed if retried later
+func IsTransient(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && slices.Contains(transientCodes, apiErr.Code)
+}
+
+// IsInvalidKey reports whether Torn rejected the key as incorrect or paused,
+// meaning it won't work again until its owner replaces or resumes it
+func IsInvalidKey(err error) bool {
+	return errors.Is(err, ErrIncorrectKey) || errors.Is(err, ErrKeyPaused)
+}
+
+// ProviderError is an error reported by a third-party API such as TornStats or YATA
+type ProviderError struct {
+	Provider string
+	Code     int
+	Message  string
+}
+
+// Error implements the error interface
+func (e *ProviderError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s error %d: %s", e.Provider, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s error: %s", e.Provider, e.Message)
+}
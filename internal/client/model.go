@@ -8,25 +8,32 @@ type ContributorInfo struct {
 }
 
 type User struct {
-	Rank         string `json:"rank"`
-	Level        int    `json:"level"`
-	Honor        int    `json:"honor"`
-	Gender       string `json:"gender"`
-	Property     string `json:"property"`
-	Signup       string `json:"signup"`
-	Awards       int    `json:"awards"`
-	Friends      int    `json:"friends"`
-	Enemies      int    `json:"enemies"`
-	ForumPosts   int    `json:"forum_posts"`
-	Karma        int    `json:"karma"`
-	Age          int    `json:"age"`
-	Role         string `json:"role"`
-	Donator      int    `json:"donator"`
-	PlayerID     int    `json:"player_id"`
-	Name         string `json:"name"`
-	PropertyID   int    `json:"property_id"`
-	Revivable    int    `json:"revivable"`
-	ProfileImage string `json:"profile_image"`
+	Rank         string     `json:"rank"`
+	Level        int        `json:"level"`
+	Honor        int        `json:"honor"`
+	Gender       string     `json:"gender"`
+	Property     string     `json:"property"`
+	Signup       string     `json:"signup"`
+	Awards       int        `json:"awards"`
+	Friends      int        `json:"friends"`
+	Enemies      int        `json:"enemies"`
+	ForumPosts   int        `json:"forum_posts"`
+	Karma        int        `json:"karma"`
+	Age          int        `json:"age"`
+	Role         string     `json:"role"`
+	Donator      int        `json:"donator"`
+	PlayerID     int        `json:"player_id"`
+	Name         string     `json:"name"`
+	PropertyID   int        `json:"property_id"`
+	Revivable    int        `json:"revivable"`
+	ProfileImage string     `json:"profile_image"`
+	LastAction   LastAction `json:"last_action"`
+}
+
+type LastAction struct {
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+	Relative  string `json:"relative"`
 }
 
 type ProfileFaction struct {
@@ -40,7 +47,72 @@ type Discord struct {
 	DiscordID string `json:"discordID"`
 }
 
+type Donation struct {
+	Name          string `json:"name"`
+	MoneyBalance  int64  `json:"money_balance"`
+	PointsBalance int64  `json:"points_balance"`
+}
+
+type NewsEntry struct {
+	News      string `json:"news"`
+	Timestamp int64  `json:"timestamp"`
+}
+
 type Key struct {
 	AccessLevel int    `json:"access_level"`
 	AccessType  string `json:"access_type"`
 }
+
+// Position is a faction position and the permissions it grants, each flag being 0 or 1
+type Position struct {
+	Default                 int `json:"default"`
+	CanUseMedicalItem       int `json:"canUseMedicalItem"`
+	CanUseBoosterItem       int `json:"canUseBoosterItem"`
+	CanUseDrugItem          int `json:"canUseDrugItem"`
+	CanUseEnergyRefill      int `json:"canUseEnergyRefill"`
+	CanUseNerveRefill       int `json:"canUseNerveRefill"`
+	CanLoanTemporaryItem    int `json:"canLoanTemporaryItem"`
+	CanLoanWeaponAndArmory  int `json:"canLoanWeaponAndArmory"`
+	CanRetrieveLoanedArmory int `json:"canRetrieveLoanedArmory"`
+	CanAccessFactionAPI     int `json:"canAccessFactionApi"`
+	CanGiveItem             int `json:"canGiveItem"`
+	CanGiveMoney            int `json:"canGiveMoney"`
+	CanGivePoints           int `json:"canGivePoints"`
+	CanManageForum          int `json:"canManageForum"`
+	CanManageApplications   int `json:"canManageApplications"`
+	CanKickMembers          int `json:"canKickMembers"`
+	CanAdjustMemberBalance  int `json:"canAdjustMemberBalance"`
+	CanManageWars           int `json:"canManageWars"`
+	CanManageUpgrades       int `json:"canManageUpgrades"`
+	CanSendNewsletter       int `json:"canSendNewsletter"`
+	CanChangeAnnouncement   int `json:"canChangeAnnouncement"`
+	CanChangeDescription    int `json:"canChangeDescription"`
+	CanManageOC2            int `json:"canManageOC2"`
+}
+
+// FactionBasic is the faction's basic information along with its member list
+type FactionBasic struct {
+	ID       int                      `json:"ID"`
+	Name     string                   `json:"name"`
+	Tag      string                   `json:"tag"`
+	Leader   int                      `json:"leader"`
+	CoLeader int                      `json:"co-leader"`
+	Members  map[string]FactionMember `json:"members"`
+}
+
+type FactionMember struct {
+	Name          string       `json:"name"`
+	Level         int          `json:"level"`
+	DaysInFaction int          `json:"days_in_faction"`
+	Position      string       `json:"position"`
+	LastAction    LastAction   `json:"last_action"`
+	Status        MemberStatus `json:"status"`
+}
+
+type MemberStatus struct {
+	Description string `json:"description"`
+	Details     string `json:"details"`
+	State       string `json:"state"`
+	Color       string `json:"color"`
+	Until       int64  `json:"until"`
+}
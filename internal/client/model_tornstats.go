@@ -0,0 +1,54 @@
+package client
+
+// tornStatsResponse is any TornStats response, all of which report whether they succeeded
+type tornStatsResponse interface {
+	result() (bool, string)
+}
+
+type tornStatsStatus struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+}
+
+func (s *tornStatsStatus) result() (bool, string) {
+	return s.Status, s.Message
+}
+
+// BattleStats are a player's battle stats as last spied
+type BattleStats struct {
+	Strength  int64 `json:"strength"`
+	Defense   int64 `json:"defense"`
+	Speed     int64 `json:"speed"`
+	Dexterity int64 `json:"dexterity"`
+	Total     int64 `json:"total"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// SpyReport is TornStats' spy on a player, compared against the key's owner
+type SpyReport struct {
+	Status         bool    `json:"status"`
+	Message        string  `json:"message"`
+	PlayerID       int     `json:"player_id"`
+	PlayerName     string  `json:"player_name"`
+	PlayerLevel    int     `json:"player_level"`
+	PlayerFaction  string  `json:"player_faction"`
+	TargetScore    float64 `json:"target_score"`
+	YourScore      float64 `json:"your_score"`
+	FairFightBonus float64 `json:"fair_fight_bonus"`
+	Difference     string  `json:"difference"`
+	BattleStats
+}
+
+// FactionSpies are TornStats' spies on a faction's members, keyed by player ID
+type FactionSpies struct {
+	ID      int                   `json:"id"`
+	Name    string                `json:"name"`
+	Members map[string]FactionSpy `json:"members"`
+}
+
+type FactionSpy struct {
+	ID    int         `json:"id"`
+	Name  string      `json:"name"`
+	Level int         `json:"level"`
+	Spy   BattleStats `json:"spy"`
+}
@@ -0,0 +1,201 @@
+package client
+
+import "strconv"
+
+// Response shapes of the Torn API v2. They are converted to the v1 models
+// wherever the Client interface shares a method between versions.
+
+type profileV2 struct {
+	ID            int          `json:"id"`
+	Name          string       `json:"name"`
+	Level         int          `json:"level"`
+	Rank          string       `json:"rank"`
+	Title         string       `json:"title"`
+	Age           int          `json:"age"`
+	SignedUp      int64        `json:"signed_up"`
+	FactionID     int          `json:"faction_id"`
+	HonorID       int          `json:"honor_id"`
+	Property      propertyV2   `json:"property"`
+	Image         string       `json:"image"`
+	Gender        string       `json:"gender"`
+	Revivable     bool         `json:"revivable"`
+	Role          string       `json:"role"`
+	Awards        int          `json:"awards"`
+	Friends       int          `json:"friends"`
+	Enemies       int          `json:"enemies"`
+	ForumPosts    int          `json:"forum_posts"`
+	Karma         int          `json:"karma"`
+	DonatorStatus string       `json:"donator_status"`
+	Status        MemberStatus `json:"status"`
+	LastAction    LastAction   `json:"last_action"`
+}
+
+type propertyV2 struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// user converts a v2 profile to the v1 user model
+func (p profileV2) user() *User {
+	user := &User{
+		Rank:         p.Rank,
+		Level:        p.Level,
+		Honor:        p.HonorID,
+		Gender:       p.Gender,
+		Property:     p.Property.Name,
+		Awards:       p.Awards,
+		Friends:      p.Friends,
+		Enemies:      p.Enemies,
+		ForumPosts:   p.ForumPosts,
+		Karma:        p.Karma,
+		Age:          p.Age,
+		Role:         p.Role,
+		PlayerID:     p.ID,
+		Name:         p.Name,
+		PropertyID:   p.Property.ID,
+		ProfileImage: p.Image,
+		LastAction:   p.LastAction,
+	}
+	if p.Revivable {
+		user.Revivable = 1
+	}
+	if p.DonatorStatus != "" {
+		user.Donator = 1
+	}
+	return user
+}
+
+type discordV2 struct {
+	DiscordID string `json:"discord_id"`
+	UserID    int    `json:"user_id"`
+}
+
+type keyInfoV2 struct {
+	Access struct {
+		Level int    `json:"level"`
+		Type  string `json:"type"`
+	} `json:"access"`
+}
+
+type contributorV2 struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	Value     int    `json:"value"`
+	InFaction bool   `json:"in_faction"`
+}
+
+// statMap converts a v2 contributor list to the v1 shape keyed by stat then player ID
+func statMap(stat string, contributors []contributorV2) StatMap {
+	players := make(map[string]ContributorInfo, len(contributors))
+	for _, c := range contributors {
+		info := ContributorInfo{Contributed: c.Value}
+		if c.InFaction {
+			info.InFaction = 1
+		}
+		players[strconv.Itoa(c.ID)] = info
+	}
+	return StatMap{stat: players}
+}
+
+type balanceV2 struct {
+	Members []struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+		Money    int64  `json:"money"`
+		Points   int64  `json:"points"`
+	} `json:"members"`
+}
+
+type newsV2 struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type factionBasicV2 struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Tag        string `json:"tag"`
+	LeaderID   int    `json:"leader_id"`
+	CoLeaderID int    `json:"co-leader_id"`
+}
+
+// FactionMemberDetails is a faction member as listed by the v2 members
+// selection, which adds flags v1 doesn't have
+type FactionMemberDetails struct {
+	ID                int          `json:"id"`
+	Name              string       `json:"name"`
+	Position          string       `json:"position"`
+	Level             int          `json:"level"`
+	DaysInFaction     int          `json:"days_in_faction"`
+	IsRevivable       bool         `json:"is_revivable"`
+	IsOnWall          bool         `json:"is_on_wall"`
+	IsInOC            bool         `json:"is_in_oc"`
+	HasEarlyDischarge bool         `json:"has_early_discharge"`
+	LastAction        LastAction   `json:"last_action"`
+	Status            MemberStatus `json:"status"`
+	ReviveSetting     string       `json:"revive_setting"`
+}
+
+// factionMember converts a v2 member to the v1 model
+func (m FactionMemberDetails) factionMember() FactionMember {
+	return FactionMember{
+		Name:          m.Name,
+		Level:         m.Level,
+		DaysInFaction: m.DaysInFaction,
+		Position:      m.Position,
+		LastAction:    m.LastAction,
+		Status:        m.Status,
+	}
+}
+
+// Crime is an organized crime (OC 2.0) of the faction
+type Crime struct {
+	ID         int         `json:"id"`
+	Name       string      `json:"name"`
+	Difficulty int         `json:"difficulty"`
+	Status     string      `json:"status"`
+	CreatedAt  int64       `json:"created_at"`
+	PlanningAt int64       `json:"planning_at"`
+	ReadyAt    int64       `json:"ready_at"`
+	ExpiredAt  int64       `json:"expired_at"`
+	ExecutedAt int64       `json:"executed_at"`
+	Slots      []CrimeSlot `json:"slots"`
+}
+
+// CrimeSlot is a position in an organized crime and who, if anyone, fills it
+type CrimeSlot struct {
+	Position           string                `json:"position"`
+	CheckpointPassRate int                   `json:"checkpoint_pass_rate"`
+	ItemRequirement    *CrimeItemRequirement `json:"item_requirement"`
+	User               *CrimeSlotUser        `json:"user"`
+}
+
+type CrimeItemRequirement struct {
+	ID          int  `json:"id"`
+	IsReusable  bool `json:"is_reusable"`
+	IsAvailable bool `json:"is_available"`
+}
+
+type CrimeSlotUser struct {
+	ID       int     `json:"id"`
+	JoinedAt int64   `json:"joined_at"`
+	Progress float64 `json:"progress"`
+}
+
+// Crime categories accepted by FetchFactionCrimes
+const (
+	CrimesAvailable  = "available"
+	CrimesRecruiting = "recruiting"
+	CrimesPlanning   = "planning"
+	CrimesSuccessful = "successful"
+	CrimesFailed     = "failed"
+	CrimesCompleted  = "completed"
+	CrimesExpired    = "expired"
+)
+
+// CrimeCategories lists every category FetchFactionCrimes accepts
+var CrimeCategories = []string{
+	CrimesAvailable, CrimesRecruiting, CrimesPlanning, CrimesSuccessful,
+	CrimesFailed, CrimesCompleted, CrimesExpired,
+}
@@ -0,0 +1,43 @@
+package client
+
+// YataMember is a faction member as known to YATA. The share fields are 1
+// when the member shares that data with their faction on YATA, 0 when they
+// don't and -1 when they aren't registered.
+type YataMember struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	LastAction     int64  `json:"last_action"`
+	Dif            int    `json:"dif"`
+	CrimesRank     int    `json:"crimes_rank"`
+	BonusScore     int    `json:"bonus_score"`
+	NNBShare       int    `json:"nnb_share"`
+	NNB            int    `json:"nnb"`
+	EnergyShare    int    `json:"energy_share"`
+	Energy         int    `json:"energy"`
+	Refill         bool   `json:"refill"`
+	DrugCD         int    `json:"drug_cd"`
+	Revive         bool   `json:"revive"`
+	Carnage        int    `json:"carnage"`
+	StatsShare     int    `json:"stats_share"`
+	StatsStrength  int64  `json:"stats_strength"`
+	StatsDefense   int64  `json:"stats_defense"`
+	StatsSpeed     int64  `json:"stats_speed"`
+	StatsDexterity int64  `json:"stats_dexterity"`
+	StatsTotal     int64  `json:"stats_total"`
+}
+
+// YataTarget is a player on a YATA target list
+type YataTarget struct {
+	Name        string  `json:"name"`
+	Level       int     `json:"level"`
+	FactionID   int     `json:"faction_id"`
+	FairFight   float64 `json:"fairFight"`
+	FlatRespect float64 `json:"flatRespect"`
+	Result      string  `json:"result"`
+	LastAttack  int64   `json:"lastAttack"`
+	Status      string  `json:"status"`
+	Note        string  `json:"note"`
+	Color       int     `json:"color"`
+	LastUpdate  int64   `json:"update"`
+}
@@ -0,0 +1,435 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoPooledKey is returned when no pooled key has the access level a request needs
+	ErrNoPooledKey = errors.New("no pooled API key has the required access level")
+	// ErrKeysSaturated is returned when every usable key is at its rate limit for longer than the pool may wait
+	ErrKeysSaturated = errors.New("every usable API key is rate limited")
+)
+
+// PooledKey asks a KeyPool to choose the key for a request
+const PooledKey = ""
+
+// Torn API key access levels
+const (
+	AccessPublic  = 1
+	AccessMinimal = 2
+	AccessLimited = 3
+	AccessFull    = 4
+)
+
+const (
+	// DefaultRateLimit is the number of requests Torn allows per key each RateWindow
+	DefaultRateLimit = 100
+	// RateWindow is the sliding window the rate limit applies to
+	RateWindow = time.Minute
+	// keyRefreshInterval is how long the pool trusts its key list before asking the source again
+	keyRefreshInterval = time.Minute
+)
+
+// KeySource lists the keys a pool may choose from
+type KeySource func(ctx context.Context) ([]string, error)
+
+// InvalidKeyHook is told about a key Torn reported as incorrect or paused
+type InvalidKeyHook func(ctx context.Context, apiKey string, err error)
+
+// PoolOption allows configuring a key pool with functional options
+type PoolOption func(*KeyPool)
+
+// WithRateLimit sets how many requests each key may make per RateWindow
+func WithRateLimit(limit int) PoolOption {
+	return func(p *KeyPool) {
+		if limit > 0 {
+			p.limit = limit
+		}
+	}
+}
+
+// WithMaxWait sets how long a request may queue for a free key before it is rejected.
+// Zero rejects as soon as every usable key is saturated.
+func WithMaxWait(wait time.Duration) PoolOption {
+	return func(p *KeyPool) {
+		p.maxWait = wait
+	}
+}
+
+// WithRetry sets how many times a request is attempted when Torn reports a
+// transient error, and the pause before the first retry, doubled for each one after
+func WithRetry(attempts int, backoff time.Duration) PoolOption {
+	return func(p *KeyPool) {
+		if attempts > 0 {
+			p.attempts = attempts
+		}
+		p.backoff = backoff
+	}
+}
+
+// KeyPool is a Client that rate limits every key it sees to the Torn limit
+// and, for requests made with PooledKey, picks the least used key from its
+// source that has the access level the request needs, moving on to the next
+// key when Torn rejects one. Transient Torn errors are retried with backoff.
+type KeyPool struct {
+	Client
+	source   KeySource
+	limit    int
+	maxWait  time.Duration
+	attempts int
+	backoff  time.Duration
+
+	mu        sync.Mutex
+	onInvalid InvalidKeyHook
+	usage     map[string][]time.Time
+	levels    map[string]int
+	benched   map[string]time.Time
+	keys      []string
+	refreshed time.Time
+}
+
+// NewKeyPool wraps a client so that every caller shares the same per-key rate limits
+func NewKeyPool(inner Client, source KeySource, opts ...PoolOption) *KeyPool {
+	pool := &KeyPool{
+		Client:   inner,
+		source:   source,
+		limit:    DefaultRateLimit,
+		maxWait:  30 * time.Second,
+		attempts: 3,
+		backoff:  2 * time.Second,
+		usage:    map[string][]time.Time{},
+		levels:   map[string]int{},
+		benched:  map[string]time.Time{},
+	}
+
+	for _, opt := range opts {
+		opt(pool)
+	}
+
+	return pool
+}
+
+// OnInvalidKey sets the hook called when Torn reports a key as incorrect or paused
+func (p *KeyPool) OnInvalidKey(hook InvalidKeyHook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onInvalid = hook
+}
+
+// reportInvalid passes a key Torn rejected for good to the hook, if one is set
+func (p *KeyPool) reportInvalid(ctx context.Context, key string, err error) {
+	p.mu.Lock()
+	hook := p.onInvalid
+	p.mu.Unlock()
+
+	if hook != nil {
+		hook(ctx, key, err)
+	}
+}
+
+// window drops the key's requests older than RateWindow and returns the rest.
+// The caller must hold p.mu.
+func (p *KeyPool) window(key string, now time.Time) []time.Time {
+	used := p.usage[key]
+	cutoff := now.Add(-RateWindow)
+	i := 0
+	for i < len(used) && !used[i].After(cutoff) {
+		i++
+	}
+	used = used[i:]
+	if len(used) == 0 {
+		delete(p.usage, key)
+	} else {
+		p.usage[key] = used
+	}
+	return used
+}
+
+// reserve records a request against the least used of the candidate keys
+// that is below the limit. When all are saturated it returns how long until
+// the first of them frees up.
+func (p *KeyPool) reserve(candidates []string) (string, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	best, bestUsed := "", p.limit
+	wait := RateWindow
+	for _, key := range candidates {
+		used := p.window(key, now)
+		if len(used) < bestUsed {
+			best, bestUsed = key, len(used)
+		}
+		if len(used) > 0 {
+			wait = min(wait, used[0].Add(RateWindow).Sub(now))
+		}
+	}
+
+	if best == "" {
+		return "", wait
+	}
+
+	p.usage[best] = append(p.usage[best], now)
+	return best, 0
+}
+
+// acquire waits until one of the candidate keys may make a request and returns it
+func (p *KeyPool) acquire(ctx context.Context, candidates []string) (string, error) {
+	deadline := time.Now().Add(p.maxWait)
+	for {
+		key, wait := p.reserve(candidates)
+		if key != "" {
+			return key, nil
+		}
+
+		if time.Now().Add(wait).After(deadline) {
+			return "", ErrKeysSaturated
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// candidates returns the pooled keys with at least the given access level,
+// refreshing the key list from the source when it is stale
+func (p *KeyPool) candidates(ctx context.Context, level int) ([]string, error) {
+	p.mu.Lock()
+	stale := time.Since(p.refreshed) > keyRefreshInterval
+	p.mu.Unlock()
+
+	if stale {
+		keys, err := p.source(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		p.mu.Lock()
+		p.keys = keys
+		p.refreshed = time.Now()
+		// Forget the access levels of keys that left the pool so a re-added key is checked again
+		for key := range p.levels {
+			if !slices.Contains(keys, key) {
+				delete(p.levels, key)
+			}
+		}
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	var eligible []string
+	for _, key := range keys {
+		if p.isBenched(key) {
+			continue
+		}
+
+		keyLevel, err := p.accessLevel(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if keyLevel >= level {
+			eligible = append(eligible, key)
+		}
+	}
+
+	return eligible, nil
+}
+
+// accessLevel returns the key's access level, asking Torn the first time the key is seen.
+// Keys Torn rejects are given level 0 so they are never picked, and reported
+// like any other request if Torn says they are invalid.
+func (p *KeyPool) accessLevel(ctx context.Context, key string) (int, error) {
+	p.mu.Lock()
+	level, ok := p.levels[key]
+	p.mu.Unlock()
+	if ok {
+		return level, nil
+	}
+
+	if _, err := p.acquire(ctx, []string{key}); err != nil {
+		return 0, err
+	}
+
+	level, err := attempt(ctx, p, key, func(key string) (int, error) {
+		return p.Client.FetchKeyDetails(ctx, key)
+	})
+	if err != nil && !IsKeyError(err) {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	p.levels[key] = level
+	p.mu.Unlock()
+
+	return level, nil
+}
+
+// bench stops the pool picking a key Torn rejected until the key list is next refreshed
+func (p *KeyPool) bench(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.benched[key] = time.Now().Add(keyRefreshInterval)
+}
+
+// isBenched reports whether the key was recently rejected by Torn
+func (p *KeyPool) isBenched(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	until, ok := p.benched[key]
+	if ok && time.Now().After(until) {
+		delete(p.benched, key)
+		return false
+	}
+	return ok
+}
+
+// withKey runs fn with the caller's key once it is within its rate limit,
+// or with pooled keys in order of least use when the caller passed PooledKey
+func withKey[T any](ctx context.Context, p *KeyPool, apiKey string, level int, fn func(key string) (T, error)) (T, error) {
+	var zero T
+
+	if apiKey != PooledKey {
+		if _, err := p.acquire(ctx, []string{apiKey}); err != nil {
+			return zero, err
+		}
+		return attempt(ctx, p, apiKey, fn)
+	}
+
+	candidates, err := p.candidates(ctx, level)
+	if err != nil {
+		return zero, err
+	}
+
+	lastErr := ErrNoPooledKey
+	for len(candidates) > 0 {
+		key, err := p.acquire(ctx, candidates)
+		if err != nil {
+			return zero, err
+		}
+
+		result, err := attempt(ctx, p, key, fn)
+		if !IsKeyError(err) {
+			return result, err
+		}
+
+		lastErr = err
+		p.bench(key)
+		candidates = slices.DeleteFunc(slices.Clone(candidates), func(k string) bool { return k == key })
+	}
+
+	return zero, lastErr
+}
+
+// attempt runs fn with a key already acquired, retrying transient Torn errors
+// with exponential backoff and reporting the key if Torn says it is invalid
+func attempt[T any](ctx context.Context, p *KeyPool, key string, fn func(key string) (T, error)) (T, error) {
+	var zero T
+
+	backoff := p.backoff
+	for try := 1; ; try++ {
+		result, err := fn(key)
+		if IsInvalidKey(err) {
+			p.reportInvalid(ctx, key, err)
+		}
+		if !IsTransient(err) || try >= p.attempts {
+			return result, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+
+		if _, err := p.acquire(ctx, []string{key}); err != nil {
+			return zero, err
+		}
+	}
+}
+
+func (p *KeyPool) FetchGymEnergy(ctx context.Context, apiKey, stat string) (StatMap, error) {
+	return withKey(ctx, p, apiKey, AccessLimited, func(key string) (StatMap, error) {
+		return p.Client.FetchGymEnergy(ctx, key, stat)
+	})
+}
+
+func (p *KeyPool) FetchTornUser(ctx context.Context, apiKey, tornID string) (*User, error) {
+	return withKey(ctx, p, apiKey, AccessPublic, func(key string) (*User, error) {
+		return p.Client.FetchTornUser(ctx, key, tornID)
+	})
+}
+
+func (p *KeyPool) FetchDiscordID(ctx context.Context, apiKey string, tornID int) (string, error) {
+	return withKey(ctx, p, apiKey, AccessPublic, func(key string) (string, error) {
+		return p.Client.FetchDiscordID(ctx, key, tornID)
+	})
+}
+
+func (p *KeyPool) FetchTornIDByDiscordID(ctx context.Context, apiKey, discordID string) (int, error) {
+	return withKey(ctx, p, apiKey, AccessPublic, func(key string) (int, error) {
+		return p.Client.FetchTornIDByDiscordID(ctx, key, discordID)
+	})
+}
+
+// FetchKeyDetails describes the given key, so it can't be asked of a pooled one
+func (p *KeyPool) FetchKeyDetails(ctx context.Context, apiKey string) (int, error) {
+	if apiKey == PooledKey {
+		return 0, errors.New("key details need an explicit API key")
+	}
+	return withKey(ctx, p, apiKey, AccessPublic, func(key string) (int, error) {
+		return p.Client.FetchKeyDetails(ctx, key)
+	})
+}
+
+func (p *KeyPool) FetchFactionBalance(ctx context.Context, apiKey string, tornID int) (int64, error) {
+	return withKey(ctx, p, apiKey, AccessLimited, func(key string) (int64, error) {
+		return p.Client.FetchFactionBalance(ctx, key, tornID)
+	})
+}
+
+func (p *KeyPool) FetchFundsNews(ctx context.Context, apiKey string) (map[string]NewsEntry, error) {
+	return withKey(ctx, p, apiKey, AccessLimited, func(key string) (map[string]NewsEntry, error) {
+		return p.Client.FetchFundsNews(ctx, key)
+	})
+}
+
+func (p *KeyPool) FetchFactionPositions(ctx context.Context, apiKey string) (map[string]Position, error) {
+	return withKey(ctx, p, apiKey, AccessLimited, func(key string) (map[string]Position, error) {
+		return p.Client.FetchFactionPositions(ctx, key)
+	})
+}
+
+func (p *KeyPool) FetchFactionBasic(ctx context.Context, apiKey string) (*FactionBasic, error) {
+	return withKey(ctx, p, apiKey, AccessPublic, func(key string) (*FactionBasic, error) {
+		return p.Client.FetchFactionBasic(ctx, key)
+	})
+}
+
+func (p *KeyPool) FetchFactionMembers(ctx context.Context, apiKey string) ([]FactionMemberDetails, error) {
+	return withKey(ctx, p, apiKey, AccessPublic, func(key string) ([]FactionMemberDetails, error) {
+		return p.Client.FetchFactionMembers(ctx, key)
+	})
+}
+
+func (p *KeyPool) FetchFactionCrimes(ctx context.Context, apiKey, category string) ([]Crime, error) {
+	return withKey(ctx, p, apiKey, AccessMinimal, func(key string) ([]Crime, error) {
+		return p.Client.FetchFactionCrimes(ctx, key, category)
+	})
+}
@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient answers key details and faction basics per key, counting the calls each key makes
+type fakeClient struct {
+	Client
+
+	mu        sync.Mutex
+	version   string
+	levels    map[string]int
+	probeErrs map[string]error
+	errs      map[string]error
+	calls     map[string]int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		levels:    map[string]int{},
+		probeErrs: map[string]error{},
+		errs:      map[string]error{},
+		calls:     map[string]int{},
+	}
+}
+
+func (f *fakeClient) call(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[key]++
+	return f.errs[key]
+}
+
+func (f *fakeClient) callCount(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[key]
+}
+
+func (f *fakeClient) Version() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.version
+}
+
+func (f *fakeClient) FetchKeyDetails(ctx context.Context, apiKey string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.probeErrs[apiKey]; err != nil {
+		return 0, err
+	}
+	return f.levels[apiKey], nil
+}
+
+func (f *fakeClient) FetchFactionBasic(ctx context.Context, apiKey string) (*FactionBasic, error) {
+	if err := f.call(apiKey); err != nil {
+		return nil, err
+	}
+	return &FactionBasic{Name: apiKey}, nil
+}
+
+func staticKeys(keys ...string) KeySource {
+	return func(ctx context.Context) ([]string, error) { return keys, nil }
+}
+
+func TestKeyPoolReserve(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	tests := []struct {
+		name     string
+		usage    map[string][]time.Time
+		wantKey  string
+		wantUsed int
+		wantWait time.Duration
+	}{
+		{
+			name:     "unused keys",
+			wantKey:  "a",
+			wantUsed: 1,
+		},
+		{
+			name:     "least used",
+			usage:    map[string][]time.Time{"a": {ago(time.Second)}},
+			wantKey:  "b",
+			wantUsed: 1,
+		},
+		{
+			name: "requests older than the window don't count",
+			usage: map[string][]time.Time{
+				"a": {ago(90 * time.Second), ago(61 * time.Second)},
+				"b": {ago(time.Second)},
+			},
+			wantKey:  "a",
+			wantUsed: 1,
+		},
+		{
+			name: "saturated waits for the first request to leave the window",
+			usage: map[string][]time.Time{
+				"a": {ago(30 * time.Second), ago(10 * time.Second)},
+				"b": {ago(50 * time.Second), ago(5 * time.Second)},
+			},
+			wantWait: 10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewKeyPool(newFakeClient(), staticKeys("a", "b"), WithRateLimit(2))
+			for key, used := range tt.usage {
+				pool.usage[key] = slices.Clone(used)
+			}
+
+			key, wait := pool.reserve([]string{"a", "b"})
+			if key != tt.wantKey {
+				t.Errorf("reserve() key = %q, want %q", key, tt.wantKey)
+			}
+			// The wait is measured from the pool's own clock, which has moved on a little
+			if wait > tt.wantWait || wait < tt.wantWait-time.Second {
+				t.Errorf("reserve() wait = %v, want about %v", wait, tt.wantWait)
+			}
+			if key != "" && len(pool.usage[key]) != tt.wantUsed {
+				t.Errorf("%q has %d requests in the window, want %d", key, len(pool.usage[key]), tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestKeyPoolRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		calls   int
+		wantErr error
+	}{
+		{name: "under the limit", limit: 3, calls: 3},
+		{name: "over the limit", limit: 2, calls: 3, wantErr: ErrKeysSaturated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeClient()
+			pool := NewKeyPool(fake, staticKeys(), WithRateLimit(tt.limit), WithMaxWait(0))
+
+			var err error
+			for range tt.calls {
+				if _, err = pool.FetchFactionBasic(context.Background(), "own-key"); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("last call returned %v, want %v", err, tt.wantErr)
+			}
+			if got := fake.callCount("own-key"); got > tt.limit {
+				t.Errorf("key made %d requests, limit is %d", got, tt.limit)
+			}
+		})
+	}
+}
+
+func TestKeyPoolAccessLevel(t *testing.T) {
+	fake := newFakeClient()
+	fake.levels = map[string]int{"public": AccessPublic, "limited": AccessLimited, "full": AccessFull}
+	pool := NewKeyPool(fake, staticKeys("public", "limited", "full"))
+
+	tests := []struct {
+		level int
+		want  []string
+	}{
+		{AccessPublic, []string{"public", "limited", "full"}},
+		{AccessLimited, []string{"limited", "full"}},
+		{AccessFull, []string{"full"}},
+	}
+
+	for _, tt := range tests {
+		got, err := pool.candidates(context.Background(), tt.level)
+		if err != nil {
+			t.Fatalf("candidates(%d) returned error: %v", tt.level, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("candidates(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestKeyPoolKeyErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		probeErr     error
+		err          error
+		wantCalls    int
+		wantReported bool
+	}{
+		{
+			name:         "incorrect key is benched and reported",
+			err:          &APIError{Code: 2, Message: "Incorrect key"},
+			wantCalls:    1,
+			wantReported: true,
+		},
+		{
+			name:         "paused key is benched and reported",
+			err:          &APIError{Code: 18, Message: "Paused API key"},
+			wantCalls:    1,
+			wantReported: true,
+		},
+		{
+			name:      "inactive owner is benched but not reported",
+			err:       &APIError{Code: 13, Message: "The key owner is inactive"},
+			wantCalls: 1,
+		},
+		{
+			name:         "incorrect key found by the access level probe",
+			probeErr:     &APIError{Code: 2, Message: "Incorrect key"},
+			wantCalls:    0,
+			wantReported: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeClient()
+			fake.levels = map[string]int{"bad": AccessLimited, "good": AccessLimited}
+			if tt.probeErr != nil {
+				fake.probeErrs["bad"] = tt.probeErr
+			}
+			if tt.err != nil {
+				fake.errs["bad"] = tt.err
+			}
+
+			pool := NewKeyPool(fake, staticKeys("bad", "good"))
+
+			var reported []string
+			pool.OnInvalidKey(func(ctx context.Context, apiKey string, err error) {
+				reported = append(reported, apiKey)
+			})
+
+			// Ties go to the first key, so the bad one is tried before the good one
+			for range 3 {
+				basic, err := pool.FetchFactionBasic(context.Background(), PooledKey)
+				if err != nil {
+					t.Fatalf("FetchFactionBasic() returned error: %v", err)
+				}
+				if basic.Name != "good" {
+					t.Errorf("FetchFactionBasic() used %q, want the good key", basic.Name)
+				}
+			}
+
+			if got := fake.callCount("bad"); got != tt.wantCalls {
+				t.Errorf("bad key made %d requests, want %d", got, tt.wantCalls)
+			}
+			if got := fake.callCount("good"); got != 3 {
+				t.Errorf("good key made %d requests, want 3", got)
+			}
+
+			var want []string
+			if tt.wantReported {
+				want = []string{"bad"}
+			}
+			if !slices.Equal(reported, want) {
+				t.Errorf("reported %v as invalid, want %v", reported, want)
+			}
+		})
+	}
+}
+
+func TestKeyPoolAllKeysRejected(t *testing.T) {
+	fake := newFakeClient()
+	fake.levels = map[string]int{"a": AccessLimited, "b": AccessLimited}
+	fake.errs = map[string]error{
+		"a": &APIError{Code: 2, Message: "Incorrect key"},
+		"b": &APIError{Code: 13, Message: "The key owner is inactive"},
+	}
+	pool := NewKeyPool(fake, staticKeys("a", "b"))
+
+	_, err := pool.FetchFactionBasic(context.Background(), PooledKey)
+	if !IsKeyError(err) {
+		t.Fatalf("FetchFactionBasic() = %v, want the last key error", err)
+	}
+
+	// Both keys are benched, so there's nothing left to try
+	_, err = pool.FetchFactionBasic(context.Background(), PooledKey)
+	if !errors.Is(err, ErrNoPooledKey) {
+		t.Errorf("FetchFactionBasic() = %v, want %v", err, ErrNoPooledKey)
+	}
+	if fake.callCount("a") != 1 || fake.callCount("b") != 1 {
+		t.Errorf("benched keys were retried: a=%d b=%d", fake.callCount("a"), fake.callCount("b"))
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TornStatsClient reads battle stat spies shared on TornStats
+type TornStatsClient interface {
+	FetchSpy(ctx context.Context, apiKey string, tornID int) (*SpyReport, error)
+	FetchFactionSpies(ctx context.Context, apiKey string, factionID int) (*FactionSpies, error)
+}
+
+type tornStatsClient struct {
+	http *client
+}
+
+// NewTornStatsClient creates a TornStats client. TornStats authenticates with
+// the key a player registered there, usually their Torn API key, which goes in
+// the request path. WithBaseURL, WithTimeout and WithHTTPClient apply as they
+// do to the Torn client.
+func NewTornStatsClient(opts ...ClientOption) TornStatsClient {
+	c := &client{
+		baseURL: string(TornStatsAPI),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &tornStatsClient{http: c}
+}
+
+// get requests a TornStats endpoint, turning a failed status into an error
+func (t *tornStatsClient) get(ctx context.Context, apiKey, endpoint string, result tornStatsResponse) error {
+	url, err := t.http.buildURL(DefaultVersion, "", apiKey+"/"+endpoint, "", nil)
+	if err != nil {
+		return err
+	}
+
+	if err := t.http.makeRequest(ctx, url, result); err != nil {
+		return err
+	}
+
+	if ok, message := result.result(); !ok {
+		return &ProviderError{Provider: "TornStats", Message: message}
+	}
+	return nil
+}
+
+// FetchSpy returns the latest spy on a player that the key's owner can see
+func (t *tornStatsClient) FetchSpy(ctx context.Context, apiKey string, tornID int) (*SpyReport, error) {
+	var parsed struct {
+		tornStatsStatus
+		Spy SpyReport `json:"spy"`
+	}
+	if err := t.get(ctx, apiKey, fmt.Sprintf("spy/user/%d", tornID), &parsed); err != nil {
+		return nil, err
+	}
+
+	if !parsed.Spy.Status {
+		return nil, &ProviderError{Provider: "TornStats", Message: parsed.Spy.Message}
+	}
+	return &parsed.Spy, nil
+}
+
+// FetchFactionSpies returns the spies the key's owner can see on a faction's members
+func (t *tornStatsClient) FetchFactionSpies(ctx context.Context, apiKey string, factionID int) (*FactionSpies, error) {
+	var parsed struct {
+		tornStatsStatus
+		Faction FactionSpies `json:"faction"`
+	}
+	if err := t.get(ctx, apiKey, fmt.Sprintf("spy/faction/%d", factionID), &parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed.Faction, nil
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTornStatsFetchSpy(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantTotal   int64
+		wantMessage string
+		wantErr     bool
+	}{
+		{
+			name:      "spy found",
+			status:    http.StatusOK,
+			body:      `{"status": true, "message": "", "spy": {"status": true, "player_id": 42, "player_name": "Target", "strength": 10, "defense": 20, "speed": 30, "dexterity": 40, "total": 100, "timestamp": 1700000000}}`,
+			wantTotal: 100,
+		},
+		{
+			name:        "request rejected",
+			status:      http.StatusOK,
+			body:        `{"status": false, "message": "User not found. Please sign up at tornstats.com"}`,
+			wantMessage: "User not found. Please sign up at tornstats.com",
+		},
+		{
+			name:        "no spy on player",
+			status:      http.StatusOK,
+			body:        `{"status": true, "message": "", "spy": {"status": false, "message": "Spy not found."}}`,
+			wantMessage: "Spy not found.",
+		},
+		{
+			name:    "server error",
+			status:  http.StatusBadGateway,
+			body:    `bad gateway`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewTornStatsClient(WithBaseURL(srv.URL))
+			spy, err := c.FetchSpy(context.Background(), "abc", 42)
+
+			if path != "/abc/spy/user/42" {
+				t.Errorf("requested %s, want /abc/spy/user/42", path)
+			}
+
+			var providerErr *ProviderError
+			switch {
+			case tt.wantMessage != "":
+				if !errors.As(err, &providerErr) {
+					t.Fatalf("FetchSpy() error = %v, want a ProviderError", err)
+				}
+				if providerErr.Provider != "TornStats" || providerErr.Message != tt.wantMessage {
+					t.Errorf("FetchSpy() error = %+v, want TornStats error %q", providerErr, tt.wantMessage)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &providerErr) {
+					t.Errorf("FetchSpy() error = %v, want a request error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("FetchSpy() returned error: %v", err)
+				}
+				if spy.PlayerID != 42 || spy.Total != tt.wantTotal || spy.Strength != 10 {
+					t.Errorf("FetchSpy() = %+v, want player 42 with total %d", spy, tt.wantTotal)
+				}
+			}
+		})
+	}
+}
+
+func TestTornStatsFetchFactionSpies(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"status": true, "message": "", "faction": {"id": 7, "name": "Kaizen", "members": {"42": {"id": 42, "name": "Target", "level": 50, "spy": {"total": 100}}}}}`))
+	}))
+	defer srv.Close()
+
+	spies, err := NewTornStatsClient(WithBaseURL(srv.URL)).FetchFactionSpies(context.Background(), "abc", 7)
+	if err != nil {
+		t.Fatalf("FetchFactionSpies() returned error: %v", err)
+	}
+
+	if path != "/abc/spy/faction/7" {
+		t.Errorf("requested %s, want /abc/spy/faction/7", path)
+	}
+	if spies.ID != 7 || spies.Members["42"].Spy.Total != 100 {
+		t.Errorf("FetchFactionSpies() = %+v, want faction 7 with member 42 at 100", spies)
+	}
+}
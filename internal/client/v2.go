@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"strconv"
+)
+
+// getV2 requests a v2 endpoint and decodes the response, returning Torn's error if it sent one
+func (t *client) getV2(ctx context.Context, apiKey, endpoint, selections string, params map[string]string, result any) error {
+	url, err := t.buildURL(VersionV2, apiKey, endpoint, selections, params)
+	if err != nil {
+		return err
+	}
+
+	var parsed struct {
+		Error *APIError `json:"error"`
+	}
+	if err := t.requestInto(ctx, url, &parsed, result); err != nil {
+		return err
+	}
+
+	if parsed.Error != nil {
+		return parsed.Error
+	}
+	return nil
+}
+
+func (t *client) fetchGymEnergyV2(ctx context.Context, apiKey, stat string) (StatMap, error) {
+	var parsed struct {
+		Contributors []contributorV2 `json:"contributors"`
+	}
+	if err := t.getV2(ctx, apiKey, "faction/contributors", "", map[string]string{"stat": stat}, &parsed); err != nil {
+		return nil, err
+	}
+
+	return statMap(stat, parsed.Contributors), nil
+}
+
+func (t *client) fetchTornUserV2(ctx context.Context, apiKey, tornID string) (*User, error) {
+	endpoint := "user/profile"
+	if tornID != "" {
+		endpoint = "user/" + tornID + "/profile"
+	}
+
+	var parsed struct {
+		Profile profileV2 `json:"profile"`
+	}
+	if err := t.getV2(ctx, apiKey, endpoint, "", nil, &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Profile.user(), nil
+}
+
+// fetchDiscordV2 looks up a Discord link by either a Torn or a Discord ID
+func (t *client) fetchDiscordV2(ctx context.Context, apiKey, id string) (*discordV2, error) {
+	var parsed struct {
+		Discord discordV2 `json:"discord"`
+	}
+	if err := t.getV2(ctx, apiKey, "user/"+id+"/discord", "", nil, &parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed.Discord, nil
+}
+
+func (t *client) fetchKeyDetailsV2(ctx context.Context, apiKey string) (int, error) {
+	var parsed struct {
+		Info keyInfoV2 `json:"info"`
+	}
+	if err := t.getV2(ctx, apiKey, "key/info", "", nil, &parsed); err != nil {
+		return 0, err
+	}
+
+	return parsed.Info.Access.Level, nil
+}
+
+func (t *client) fetchFactionBalanceV2(ctx context.Context, apiKey string, tornID int) (int64, error) {
+	var parsed struct {
+		Balance balanceV2 `json:"balance"`
+	}
+	if err := t.getV2(ctx, apiKey, "faction/balance", "", nil, &parsed); err != nil {
+		return 0, err
+	}
+
+	for _, m := range parsed.Balance.Members {
+		if m.ID == tornID {
+			return m.Money, nil
+		}
+	}
+	return 0, ErrMemberNotInFaction
+}
+
+// fetchFundsNewsV2 reads the money given out by the faction, keeping the
+// links in the text that payouts are matched on
+func (t *client) fetchFundsNewsV2(ctx context.Context, apiKey string) (map[string]NewsEntry, error) {
+	params := map[string]string{"cat": "giveFunds", "striptags": "false"}
+
+	var parsed struct {
+		News []newsV2 `json:"news"`
+	}
+	if err := t.getV2(ctx, apiKey, "faction/news", "", params, &parsed); err != nil {
+		return nil, err
+	}
+
+	news := make(map[string]NewsEntry, len(parsed.News))
+	for _, n := range parsed.News {
+		news[n.ID] = NewsEntry{News: n.Text, Timestamp: n.Timestamp}
+	}
+	return news, nil
+}
+
+func (t *client) fetchFactionBasicV2(ctx context.Context, apiKey string) (*FactionBasic, error) {
+	var parsed struct {
+		Basic   factionBasicV2         `json:"basic"`
+		Members []FactionMemberDetails `json:"members"`
+	}
+	if err := t.getV2(ctx, apiKey, "faction", "basic,members", nil, &parsed); err != nil {
+		return nil, err
+	}
+
+	basic := &FactionBasic{
+		ID:       parsed.Basic.ID,
+		Name:     parsed.Basic.Name,
+		Tag:      parsed.Basic.Tag,
+		Leader:   parsed.Basic.LeaderID,
+		CoLeader: parsed.Basic.CoLeaderID,
+		Members:  make(map[string]FactionMember, len(parsed.Members)),
+	}
+	for _, m := range parsed.Members {
+		basic.Members[strconv.Itoa(m.ID)] = m.factionMember()
+	}
+	return basic, nil
+}
+
+// FetchFactionMembers returns the faction's members with the v2 only flags
+// such as whether they are in an organized crime. It always uses v2.
+func (t *client) FetchFactionMembers(ctx context.Context, apiKey string) ([]FactionMemberDetails, error) {
+	var parsed struct {
+		Members []FactionMemberDetails `json:"members"`
+	}
+	if err := t.getV2(ctx, apiKey, "faction/members", "", nil, &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Members, nil
+}
+
+// FetchFactionCrimes returns the faction's organized crimes in a category,
+// or every category when it is empty. It always uses v2.
+func (t *client) FetchFactionCrimes(ctx context.Context, apiKey, category string) ([]Crime, error) {
+	var params map[string]string
+	if category != "" {
+		params = map[string]string{"cat": category}
+	}
+
+	var parsed struct {
+		Crimes []Crime `json:"crimes"`
+	}
+	if err := t.getV2(ctx, apiKey, "faction/crimes", "", params, &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Crimes, nil
+}
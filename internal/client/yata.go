@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// YataClient reads the faction and target data members share with YATA
+type YataClient interface {
+	FetchFactionMembers(ctx context.Context, apiKey string) (map[string]YataMember, error)
+	FetchTargets(ctx context.Context, apiKey string) (map[string]YataTarget, error)
+}
+
+type yataClient struct {
+	http *client
+}
+
+// NewYataClient creates a YATA client. YATA authenticates with the Torn API
+// key of a player registered there, passed as the key query parameter.
+// WithBaseURL, WithTimeout and WithHTTPClient apply as they do to the Torn client.
+func NewYataClient(opts ...ClientOption) YataClient {
+	c := &client{
+		baseURL: string(YataAPI),
+		version: "v1",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &yataClient{http: c}
+}
+
+// get requests a YATA endpoint, returning YATA's error if it sent one
+func (y *yataClient) get(ctx context.Context, apiKey, endpoint string, result any) error {
+	url, err := y.http.buildURL(y.http.Version(), apiKey, endpoint, "", nil)
+	if err != nil {
+		return err
+	}
+
+	var parsed struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"error"`
+		} `json:"error"`
+	}
+	if err := y.http.requestInto(ctx, url, &parsed, result); err != nil {
+		return err
+	}
+
+	if parsed.Error != nil {
+		return &ProviderError{Provider: "YATA", Code: parsed.Error.Code, Message: parsed.Error.Message}
+	}
+	return nil
+}
+
+// FetchFactionMembers returns the key owner's faction members as known to YATA, keyed by player ID
+func (y *yataClient) FetchFactionMembers(ctx context.Context, apiKey string) (map[string]YataMember, error) {
+	var parsed struct {
+		Members map[string]YataMember `json:"members"`
+	}
+	if err := y.get(ctx, apiKey, "faction/members/", &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Members, nil
+}
+
+// FetchTargets returns the key owner's YATA target list, keyed by player ID
+func (y *yataClient) FetchTargets(ctx context.Context, apiKey string) (map[string]YataTarget, error) {
+	var parsed struct {
+		Targets map[string]YataTarget `json:"targets"`
+	}
+	if err := y.get(ctx, apiKey, "targets/export/", &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Targets, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestYataFetchFactionMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantName string
+		wantCode int
+		wantErr  bool
+	}{
+		{
+			name:     "members",
+			status:   http.StatusOK,
+			body:     `{"members": {"42": {"id": 42, "name": "Member", "stats_share": 1, "stats_total": 100}}}`,
+			wantName: "Member",
+		},
+		{
+			name:     "error reported",
+			status:   http.StatusOK,
+			body:     `{"error": {"code": 2, "error": "Player not found"}}`,
+			wantCode: 2,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			body:    `oops`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, key string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path, key = r.URL.Path, r.URL.Query().Get("key")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			members, err := NewYataClient(WithBaseURL(srv.URL)).FetchFactionMembers(context.Background(), "abc")
+
+			if path != "/v1/faction/members/" || key != "abc" {
+				t.Errorf("requested %s with key %q, want /v1/faction/members/ with key abc", path, key)
+			}
+
+			var providerErr *ProviderError
+			switch {
+			case tt.wantCode != 0:
+				if !errors.As(err, &providerErr) {
+					t.Fatalf("FetchFactionMembers() error = %v, want a ProviderError", err)
+				}
+				if providerErr.Provider != "YATA" || providerErr.Code != tt.wantCode {
+					t.Errorf("FetchFactionMembers() error = %+v, want YATA code %d", providerErr, tt.wantCode)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &providerErr) {
+					t.Errorf("FetchFactionMembers() error = %v, want a request error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("FetchFactionMembers() returned error: %v", err)
+				}
+				if members["42"].Name != tt.wantName || members["42"].StatsTotal != 100 {
+					t.Errorf("FetchFactionMembers() = %+v, want member 42 named %s", members, tt.wantName)
+				}
+			}
+		})
+	}
+}
+
+func TestYataFetchTargets(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"targets": {"42": {"name": "Target", "level": 50, "fairFight": 2.5, "update": 1700000000}}}`))
+	}))
+	defer srv.Close()
+
+	targets, err := NewYataClient(WithBaseURL(srv.URL)).FetchTargets(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("FetchTargets() returned error: %v", err)
+	}
+
+	if path != "/v1/targets/export/" {
+		t.Errorf("requested %s, want /v1/targets/export/", path)
+	}
+	if target := targets["42"]; target.FairFight != 2.5 || target.LastUpdate != 1700000000 {
+		t.Errorf("FetchTargets() = %+v, want target 42 at fair fight 2.5", targets)
+	}
+}
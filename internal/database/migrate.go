@@ -0,0 +1,233 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID keeps two instances from migrating the same database at once
+const migrationLockID = 7_311_042
+
+// Migration is a numbered schema change with the SQL to apply and revert it.
+// Files are named NNNN_name.up.sql and NNNN_name.down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a migration and when it was applied, if it has been
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrations returns the embedded migrations ordered by version
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s must end in .up.sql or .down.sql", name)
+		}
+
+		versionPart, title, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionPart)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %s must start with a version number", name)
+		}
+
+		contents, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: title}
+			byVersion[version] = m
+		}
+		if m.Name != title {
+			return nil, fmt.Errorf("migration %d has files with different names", version)
+		}
+
+		if direction == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+
+	return migrations, nil
+}
+
+// MigrateUp applies every migration that hasn't been applied yet, each in its own transaction
+func MigrateUp(ctx context.Context, db *pgxpool.Pool) error {
+	return withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		states, err := migrationStates(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, state := range states {
+			if state.AppliedAt != nil {
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, state.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					state.Version, state.Name,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %04d_%s: %w", state.Version, state.Name, err)
+			}
+
+			log.Printf("Applied migration %04d_%s", state.Version, state.Name)
+		}
+
+		return nil
+	})
+}
+
+// MigrateDown reverts the given number of most recently applied migrations
+func MigrateDown(ctx context.Context, db *pgxpool.Pool, steps int) error {
+	return withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		states, err := migrationStates(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(states) - 1; i >= 0 && steps > 0; i-- {
+			state := states[i]
+			if state.AppliedAt == nil {
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, state.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, state.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %04d_%s: %w", state.Version, state.Name, err)
+			}
+
+			log.Printf("Reverted migration %04d_%s", state.Version, state.Name)
+			steps--
+		}
+
+		return nil
+	})
+}
+
+// MigrationStatus lists every embedded migration and when it was applied
+func MigrationStatus(ctx context.Context, db *pgxpool.Pool) ([]MigrationState, error) {
+	var states []MigrationState
+	err := withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		var err error
+		states, err = migrationStates(ctx, conn)
+		return err
+	})
+
+	return states, err
+}
+
+// withMigrationLock runs fn on a single connection holding the migration lock,
+// creating the schema_migrations table first if needed
+func withMigrationLock(ctx context.Context, db *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		name       text        NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// migrationStates pairs each embedded migration with its schema_migrations row
+func migrationStates(ctx context.Context, conn *pgxpool.Conn) ([]MigrationState, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[int]time.Time{}
+	var (
+		version   int
+		appliedAt time.Time
+	)
+	_, err = pgx.ForEachRow(rows, []any{&version, &appliedAt}, func() error {
+		applied[version] = appliedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		state := MigrationState{Migration: m}
+		if at, ok := applied[m.Version]; ok {
+			state.AppliedAt = &at
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
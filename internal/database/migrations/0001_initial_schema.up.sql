@@ -0,0 +1,72 @@
+-- Tables the application has relied on since before migrations existed.
+-- IF NOT EXISTS lets databases created by hand adopt this migration as is.
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id            serial PRIMARY KEY,
+    torn_id       integer     NOT NULL UNIQUE,
+    email         text        NOT NULL UNIQUE,
+    password_hash text        NOT NULL,
+    api_key       text        NOT NULL DEFAULT '',
+    discord_id    text        NOT NULL DEFAULT '',
+    created_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    player_id     integer PRIMARY KEY,
+    name          text    NOT NULL DEFAULT '',
+    rank          text    NOT NULL DEFAULT '',
+    level         integer NOT NULL DEFAULT 0,
+    honor         integer NOT NULL DEFAULT 0,
+    gender        text    NOT NULL DEFAULT '',
+    property      text    NOT NULL DEFAULT '',
+    signup        text    NOT NULL DEFAULT '',
+    awards        integer NOT NULL DEFAULT 0,
+    friends       integer NOT NULL DEFAULT 0,
+    enemies       integer NOT NULL DEFAULT 0,
+    forum_posts   integer NOT NULL DEFAULT 0,
+    karma         integer NOT NULL DEFAULT 0,
+    age           integer NOT NULL DEFAULT 0,
+    role          text    NOT NULL DEFAULT '',
+    donator       integer NOT NULL DEFAULT 0,
+    property_id   integer NOT NULL DEFAULT 0,
+    revivable     integer NOT NULL DEFAULT 0,
+    profile_image text    NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id            serial PRIMARY KEY,
+    name          text    NOT NULL UNIQUE,
+    description   text    NOT NULL DEFAULT '',
+    is_leadership boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          serial PRIMARY KEY,
+    name        text NOT NULL UNIQUE,
+    description text NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       integer NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id integer NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id integer NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    role_id integer NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_gym_energy_log (
+    torn_id   text        NOT NULL,
+    strength  bigint      NOT NULL DEFAULT 0,
+    speed     bigint      NOT NULL DEFAULT 0,
+    defense   bigint      NOT NULL DEFAULT 0,
+    dexterity bigint      NOT NULL DEFAULT 0,
+    total     bigint      NOT NULL DEFAULT 0,
+    timestamp timestamptz NOT NULL,
+    PRIMARY KEY (torn_id, timestamp)
+);
+
+CREATE INDEX IF NOT EXISTS user_gym_energy_log_timestamp_idx ON user_gym_energy_log (timestamp);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS accounts_discord_id_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS last_action_at,
+    DROP COLUMN IF EXISTS last_action_status,
+    DROP COLUMN IF EXISTS discord_id;
@@ -0,0 +1,9 @@
+-- Profiles record the linked Discord account and when they were last
+-- refreshed. Existing rows start at the epoch so they count as stale.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS discord_id         text UNIQUE,
+    ADD COLUMN IF NOT EXISTS last_action_status text        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_action_at     timestamptz NOT NULL DEFAULT 'epoch',
+    ADD COLUMN IF NOT EXISTS updated_at         timestamptz NOT NULL DEFAULT 'epoch';
+
+CREATE INDEX IF NOT EXISTS accounts_discord_id_idx ON accounts (discord_id);
@@ -0,0 +1,24 @@
+CREATE TABLE banker_requests (
+    id                   serial PRIMARY KEY,
+    guild_id             text        NOT NULL,
+    requester_discord_id text        NOT NULL,
+    requester_name       text        NOT NULL,
+    requester_torn_id    integer     NOT NULL,
+    amount               bigint      NOT NULL CHECK (amount > 0),
+    status               text        NOT NULL,
+    handler_discord_id   text        NOT NULL DEFAULT '',
+    handler_name         text        NOT NULL DEFAULT '',
+    closed_by_discord_id text        NOT NULL DEFAULT '',
+    closed_by_name       text        NOT NULL DEFAULT '',
+    admin_channel_id     text        NOT NULL DEFAULT '',
+    admin_message_id     text        NOT NULL DEFAULT '',
+    verified_news_id     text        NOT NULL DEFAULT '',
+    flag_reason          text        NOT NULL DEFAULT '',
+    created_at           timestamptz NOT NULL DEFAULT now(),
+    updated_at           timestamptz NOT NULL DEFAULT now(),
+    claimed_at           timestamptz,
+    closed_at            timestamptz,
+    verified_at          timestamptz
+);
+
+CREATE INDEX banker_requests_status_idx ON banker_requests (status, created_at);
@@ -0,0 +1,8 @@
+CREATE TABLE guild_settings (
+    guild_id          text PRIMARY KEY,
+    banker_channel_id text        NOT NULL DEFAULT '',
+    log_channel_id    text        NOT NULL DEFAULT '',
+    verified_role_id  text        NOT NULL DEFAULT '',
+    enabled_features  text[]      NOT NULL DEFAULT '{}',
+    updated_at        timestamptz NOT NULL DEFAULT now()
+);
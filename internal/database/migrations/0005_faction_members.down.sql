@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS faction_member_events;
+DROP TABLE IF EXISTS faction_members;
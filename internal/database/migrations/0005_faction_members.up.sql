@@ -0,0 +1,22 @@
+CREATE TABLE faction_members (
+    torn_id            integer PRIMARY KEY,
+    name               text        NOT NULL,
+    level              integer     NOT NULL DEFAULT 0,
+    days_in_faction    integer     NOT NULL DEFAULT 0,
+    position           text        NOT NULL DEFAULT '',
+    last_action_status text        NOT NULL DEFAULT '',
+    last_action_at     timestamptz NOT NULL,
+    status             text        NOT NULL DEFAULT '',
+    status_description text        NOT NULL DEFAULT '',
+    updated_at         timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE faction_member_events (
+    id         serial PRIMARY KEY,
+    torn_id    integer     NOT NULL,
+    name       text        NOT NULL,
+    type       text        NOT NULL CHECK (type IN ('joined', 'left')),
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX faction_member_events_created_at_idx ON faction_member_events (created_at);
@@ -0,0 +1,10 @@
+CREATE TABLE job_runs (
+    id          serial PRIMARY KEY,
+    name        text        NOT NULL,
+    status      text        NOT NULL,
+    error       text        NOT NULL DEFAULT '',
+    started_at  timestamptz NOT NULL DEFAULT now(),
+    finished_at timestamptz
+);
+
+CREATE INDEX job_runs_name_idx ON job_runs (name, started_at);
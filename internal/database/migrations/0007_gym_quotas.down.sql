@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS leaves_of_absence;
+DROP TABLE IF EXISTS gym_quotas;
@@ -0,0 +1,21 @@
+CREATE TABLE gym_quotas (
+    id            serial PRIMARY KEY,
+    role_id       integer REFERENCES roles (id) ON DELETE CASCADE,
+    weekly_energy integer     NOT NULL CHECK (weekly_energy >= 0),
+    updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+-- One quota per role, and a single global quota where role_id is NULL
+CREATE UNIQUE INDEX gym_quotas_role_id_key ON gym_quotas (COALESCE(role_id, 0));
+
+CREATE TABLE leaves_of_absence (
+    id         serial PRIMARY KEY,
+    torn_id    integer     NOT NULL,
+    starts_at  timestamptz NOT NULL,
+    ends_at    timestamptz NOT NULL CHECK (ends_at > starts_at),
+    reason     text        NOT NULL DEFAULT '',
+    created_by integer     NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX leaves_of_absence_ends_at_idx ON leaves_of_absence (ends_at);
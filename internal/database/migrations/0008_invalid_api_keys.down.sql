@@ -0,0 +1 @@
+ALTER TABLE accounts DROP COLUMN IF EXISTS api_key_invalid_at;
@@ -0,0 +1,4 @@
+-- Keys Torn reports as incorrect or paused are marked so they stop being
+-- used until their owner stores a working one.
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS api_key_invalid_at timestamptz;
@@ -0,0 +1,8 @@
+-- Torn API responses kept between restarts by the client cache
+CREATE TABLE IF NOT EXISTS torn_cache (
+    key        text        PRIMARY KEY,
+    value      jsonb       NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS torn_cache_expires_at_idx ON torn_cache (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE faction_members DROP COLUMN IF EXISTS in_oc;
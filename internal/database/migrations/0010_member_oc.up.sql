@@ -0,0 +1,3 @@
+-- Whether the member is taking part in an organized crime, from the v2 members selection
+ALTER TABLE faction_members
+    ADD COLUMN IF NOT EXISTS in_oc boolean NOT NULL DEFAULT false;
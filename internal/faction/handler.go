@@ -0,0 +1,268 @@
+package faction
+
+import (
+	"errors"
+	"kaizen-hq/internal/role"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultEventDays is how far back membership events are listed when no period is given
+const defaultEventDays = 7
+
+// defaultGymPeriod is the period the gym leaderboard covers when no range is given
+const defaultGymPeriod = PeriodWeek
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListMembers returns the faction roster along with who joined or left over the last ?days
+func (h *Handler) ListMembers(c *gin.Context) {
+	days := defaultEventDays
+	if daysParam := c.Query("days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive whole number"})
+			return
+		}
+		days = parsed
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.service.MemberEvents(c.Request.Context(), time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members, "events": events})
+}
+
+// Crimes lists the faction's organized crimes, optionally only those in the ?category
+func (h *Handler) Crimes(c *gin.Context) {
+	crimes, err := h.service.Crimes(c.Request.Context(), c.Query("category"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"crimes": crimes})
+}
+
+// GymLeaderboard ranks members by the energy trained between ?from and ?to
+// (dates or RFC 3339 times), or over the ?period of day, week or month
+// leading up to ?to, into ?stat (total by default)
+func (h *Handler) GymLeaderboard(c *gin.Context) {
+	from, to, ok := gymRange(c)
+	if !ok {
+		return
+	}
+
+	stat := c.DefaultQuery("stat", StatTotal)
+	if !slices.Contains(GymStats, stat) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "stat must be one of total, strength, speed, defense or dexterity"})
+		return
+	}
+
+	board, err := h.service.GymLeaderboard(c.Request.Context(), from, to, stat)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"leaderboard": board})
+}
+
+// MemberGym returns the energy a member trained on each day of the range
+func (h *Handler) MemberGym(c *gin.Context) {
+	tornID, err := strconv.Atoi(c.Param("tornID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tornID must be a whole number"})
+		return
+	}
+
+	from, to, ok := gymRange(c)
+	if !ok {
+		return
+	}
+
+	days, err := h.service.DailyGymGains(c.Request.Context(), tornID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "days": days})
+}
+
+// gymRange reads the ?from, ?to and ?period query parameters, responding with 400 if they're invalid
+func gymRange(c *gin.Context) (from, to time.Time, ok bool) {
+	to = time.Now()
+	if toParam := c.Query("to"); toParam != "" {
+		parsed, err := parseTime(toParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date or RFC 3339 time"})
+			return from, to, false
+		}
+		to = parsed
+	}
+
+	if fromParam := c.Query("from"); fromParam != "" {
+		parsed, err := parseTime(fromParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date or RFC 3339 time"})
+			return from, to, false
+		}
+		from = parsed
+	} else {
+		start, valid := PeriodStart(c.DefaultQuery("period", defaultGymPeriod), to)
+		if !valid {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "period must be day, week or month"})
+			return from, to, false
+		}
+		from = start
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return from, to, false
+	}
+
+	return from, to, true
+}
+
+// parseTime accepts either a plain date, taken as midnight UTC, or an RFC 3339 time
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// handleError maps service errors to HTTP responses
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrQuotaNotFound), errors.Is(err, ErrLeaveNotFound), errors.Is(err, role.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidLeave), errors.Is(err, ErrInvalidCrimeCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *Handler) ListGymQuotas(c *gin.Context) {
+	quotas, err := h.service.GymQuotas(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quotas": quotas})
+}
+
+// SetGymQuota sets the quota of the given role, or the global quota when no role is given
+func (h *Handler) SetGymQuota(c *gin.Context) {
+	var req GymQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	quota, err := h.service.SetGymQuota(c.Request.Context(), req.RoleID, req.WeeklyEnergy)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quota": quota})
+}
+
+func (h *Handler) DeleteGymQuota(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("quotaID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "quotaID must be a whole number"})
+		return
+	}
+
+	if err := h.service.DeleteGymQuota(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListLeaves(c *gin.Context) {
+	leaves, err := h.service.Leaves(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"leaves": leaves})
+}
+
+func (h *Handler) RecordLeave(c *gin.Context) {
+	var req LeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	leave, err := h.service.RecordLeave(c.Request.Context(), &LeaveOfAbsence{
+		TornID:    req.TornID,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Reason:    req.Reason,
+		CreatedBy: c.Keys["torn_id"].(int),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"leave": leave})
+}
+
+func (h *Handler) DeleteLeave(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("leaveID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "leaveID must be a whole number"})
+		return
+	}
+
+	if err := h.service.DeleteLeave(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GymEvaluation previews how members measure up against their quotas over the past week
+func (h *Handler) GymEvaluation(c *gin.Context) {
+	to := time.Now()
+
+	evaluation, err := h.service.EvaluateGymQuotas(c.Request.Context(), to.Add(-QuotaPeriod), to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"evaluation": evaluation})
+}
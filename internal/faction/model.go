@@ -1,6 +1,8 @@
 package faction
 
 import (
+	"kaizen-hq/internal/client"
+	"kaizen-hq/internal/permission"
 	"time"
 )
 
@@ -14,6 +16,129 @@ type UserGymEnergy struct {
 	Timestamp time.Time
 }
 
+// Gym stats a leaderboard can be ranked by
+const (
+	StatStrength  = "strength"
+	StatSpeed     = "speed"
+	StatDefense   = "defense"
+	StatDexterity = "dexterity"
+	StatTotal     = "total"
+)
+
+// GymStats lists every stat a leaderboard can be ranked by
+var GymStats = []string{StatTotal, StatStrength, StatSpeed, StatDefense, StatDexterity}
+
+// Periods gym gains can be reported over
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// PeriodStart returns the start of the period ending at the given time, or
+// false if the period isn't one of day, week or month
+func PeriodStart(period string, end time.Time) (time.Time, bool) {
+	switch period {
+	case PeriodDay:
+		return end.AddDate(0, 0, -1), true
+	case PeriodWeek:
+		return end.AddDate(0, 0, -7), true
+	case PeriodMonth:
+		return end.AddDate(0, -1, 0), true
+	}
+	return time.Time{}, false
+}
+
+// GymGain is the gym energy a member trained over a period
+type GymGain struct {
+	TornID    int    `json:"torn_id"`
+	Name      string `json:"name"`
+	Strength  int    `json:"strength"`
+	Speed     int    `json:"speed"`
+	Defense   int    `json:"defense"`
+	Dexterity int    `json:"dexterity"`
+	Total     int    `json:"total"`
+}
+
+// Stat returns the energy trained into the named stat
+func (g *GymGain) Stat(stat string) int {
+	switch stat {
+	case StatStrength:
+		return g.Strength
+	case StatSpeed:
+		return g.Speed
+	case StatDefense:
+		return g.Defense
+	case StatDexterity:
+		return g.Dexterity
+	}
+	return g.Total
+}
+
+// gymGain is the energy trained between two snapshots of the same member. A
+// counter that went down means the member left and rejoined, so only what was
+// trained since rejoining is counted.
+func gymGain(start, end *UserGymEnergy) GymGain {
+	diff := func(a, b int) int {
+		if b < a {
+			return b
+		}
+		return b - a
+	}
+
+	g := GymGain{
+		Strength:  diff(start.Strength, end.Strength),
+		Speed:     diff(start.Speed, end.Speed),
+		Defense:   diff(start.Defense, end.Defense),
+		Dexterity: diff(start.Dexterity, end.Dexterity),
+	}
+	g.Total = g.Strength + g.Speed + g.Defense + g.Dexterity
+	return g
+}
+
+// GymLeaderboard ranks members by the energy trained into a stat over a period
+type GymLeaderboard struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Stat    string    `json:"stat"`
+	Entries []GymGain `json:"entries"`
+}
+
+// Idle returns the members who trained nothing into the ranked stat
+func (l *GymLeaderboard) Idle() []GymGain {
+	var idle []GymGain
+	for _, e := range l.Entries {
+		if e.Stat(l.Stat) == 0 {
+			idle = append(idle, e)
+		}
+	}
+	return idle
+}
+
+// DailyGymGain is the gym energy a member trained on one day
+type DailyGymGain struct {
+	Date time.Time `json:"date"`
+	GymGain
+}
+
+// GymSummary describes how much gym energy a member trained over a period
+type GymSummary struct {
+	Days      int
+	Strength  int
+	Speed     int
+	Defense   int
+	Dexterity int
+	Total     int
+}
+
+// PerDay returns the average total energy trained per day
+func (g *GymSummary) PerDay() int {
+	if g.Days <= 0 {
+		return 0
+	}
+	return g.Total / g.Days
+}
+
 type Faction struct {
 	ID        int    `json:"ID"`
 	Name      string `json:"name"`
@@ -27,28 +152,196 @@ type Faction struct {
 	BestChain int    `json:"best_chain"`
 }
 
-type Position struct {
-	Default                 int `json:"default"`
-	CanUseMedicalItem       int `json:"canUseMedicalItem"`
-	CanUseBoosterItem       int `json:"canUseBoosterItem"`
-	CanUseDrugItem          int `json:"canUseDrugItem"`
-	CanUseEnergyRefill      int `json:"canUseEnergyRefill"`
-	CanUseNerveRefill       int `json:"canUseNerveRefill"`
-	CanLoanTemporaryItem    int `json:"canLoanTemporaryItem"`
-	CanLoanWeaponAndArmory  int `json:"canLoanWeaponAndArmory"`
-	CanRetrieveLoanedArmory int `json:"canRetrieveLoanedArmory"`
-	CanAccessFactionAPI     int `json:"canAccessFactionApi"`
-	CanGiveItem             int `json:"canGiveItem"`
-	CanGiveMoney            int `json:"canGiveMoney"`
-	CanGivePoints           int `json:"canGivePoints"`
-	CanManageForum          int `json:"canManageForum"`
-	CanManageApplications   int `json:"canManageApplications"`
-	CanKickMembers          int `json:"canKickMembers"`
-	CanAdjustMemberBalance  int `json:"canAdjustMemberBalance"`
-	CanManageWars           int `json:"canManageWars"`
-	CanManageUpgrades       int `json:"canManageUpgrades"`
-	CanSendNewsletter       int `json:"canSendNewsletter"`
-	CanChangeAnnouncement   int `json:"canChangeAnnouncement"`
-	CanChangeDescription    int `json:"canChangeDescription"`
-	CanManageOC2            int `json:"canManageOC2"`
+// Position is a faction position as reported by the Torn API
+type Position = client.Position
+
+// PositionRolePrefix marks the roles that mirror a Torn faction position,
+// so the position sync knows which roles it owns
+const PositionRolePrefix = "torn:"
+
+// Positions built into Torn that the positions selection doesn't list
+const (
+	PositionLeader   = "Leader"
+	PositionCoLeader = "Co-leader"
+)
+
+// leadershipPosition holds every flag, as the leader and co-leader can do anything
+var leadershipPosition = Position{
+	CanUseMedicalItem:       1,
+	CanUseBoosterItem:       1,
+	CanUseDrugItem:          1,
+	CanUseEnergyRefill:      1,
+	CanUseNerveRefill:       1,
+	CanLoanTemporaryItem:    1,
+	CanLoanWeaponAndArmory:  1,
+	CanRetrieveLoanedArmory: 1,
+	CanAccessFactionAPI:     1,
+	CanGiveItem:             1,
+	CanGiveMoney:            1,
+	CanGivePoints:           1,
+	CanManageForum:          1,
+	CanManageApplications:   1,
+	CanKickMembers:          1,
+	CanAdjustMemberBalance:  1,
+	CanManageWars:           1,
+	CanManageUpgrades:       1,
+	CanSendNewsletter:       1,
+	CanChangeAnnouncement:   1,
+	CanChangeDescription:    1,
+	CanManageOC2:            1,
+}
+
+// PositionRoleName is the name of the role mirroring a faction position
+func PositionRoleName(position string) string {
+	return PositionRolePrefix + position
+}
+
+// PositionPermissions derives the application permissions a faction position grants
+func PositionPermissions(name string, p *Position) []string {
+	var perms []string
+
+	if p.CanAccessFactionAPI == 1 {
+		perms = append(perms, permission.ViewLogs)
+	}
+	if p.CanGiveMoney == 1 {
+		perms = append(perms, permission.BankerFulfill)
+	}
+	if p.CanManageApplications == 1 {
+		perms = append(perms, permission.VerifyAll)
+	}
+
+	// Configuring the bot and handing out access is kept to the faction's leaders
+	if name == PositionLeader || name == PositionCoLeader {
+		perms = append(perms, permission.ConfigEdit, permission.RolesManage)
+	}
+
+	return perms
+}
+
+// PositionSync reports what a faction position sync changed
+type PositionSync struct {
+	Roles    int
+	Assigned int
+	Removed  int
+}
+
+// Member is a faction member as of the latest roster sync
+type Member struct {
+	TornID            int       `json:"torn_id"`
+	Name              string    `json:"name"`
+	Level             int       `json:"level"`
+	DaysInFaction     int       `json:"days_in_faction"`
+	Position          string    `json:"position"`
+	LastActionStatus  string    `json:"last_action_status"`
+	LastActionAt      time.Time `json:"last_action_at"`
+	Status            string    `json:"status"`
+	StatusDescription string    `json:"status_description"`
+	InOC              bool      `json:"in_oc"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// MemberEventType is what happened to a member's place in the faction
+type MemberEventType string
+
+const (
+	MemberJoined MemberEventType = "joined"
+	MemberLeft   MemberEventType = "left"
+)
+
+// MemberEvent records a member joining or leaving the faction
+type MemberEvent struct {
+	ID        int             `json:"id"`
+	TornID    int             `json:"torn_id"`
+	Name      string          `json:"name"`
+	Type      MemberEventType `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// RosterSync reports the outcome of a faction roster sync
+type RosterSync struct {
+	Members int
+	Joined  []MemberEvent
+	Left    []MemberEvent
+}
+
+// QuotaPeriod is the period a gym quota covers
+const QuotaPeriod = 7 * 24 * time.Hour
+
+// GymQuota is the minimum gym energy members must train each week. A quota
+// without a role applies to everyone, while role quotas override it for the
+// members of that role.
+type GymQuota struct {
+	ID           int       `json:"id"`
+	RoleID       *int      `json:"role_id"`
+	WeeklyEnergy int       `json:"weekly_energy"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GymQuotaRequest struct {
+	RoleID       *int `json:"role_id"`
+	WeeklyEnergy int  `json:"weekly_energy" binding:"min=0"`
+}
+
+// LeaveOfAbsence exempts a member from gym quotas while it lasts
+type LeaveOfAbsence struct {
+	ID        int       `json:"id"`
+	TornID    int       `json:"torn_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedBy int       `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LeaveRequest struct {
+	TornID   int       `json:"torn_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+// Overlaps reports whether any part of the leave falls between the two times
+func (l *LeaveOfAbsence) Overlaps(from, to time.Time) bool {
+	return l.StartsAt.Before(to) && l.EndsAt.After(from)
+}
+
+// QuotaResult is how a member measured up against their gym quota
+type QuotaResult struct {
+	GymGain
+	Required int  `json:"required"`
+	Exempt   bool `json:"exempt"`
+}
+
+// Met reports whether the member trained enough or was excused
+func (r *QuotaResult) Met() bool {
+	return r.Exempt || r.Total >= r.Required
+}
+
+// QuotaEvaluation is the outcome of checking every member against their gym quota
+type QuotaEvaluation struct {
+	From    time.Time     `json:"from"`
+	To      time.Time     `json:"to"`
+	Results []QuotaResult `json:"results"`
+}
+
+// Below returns the members who trained less than their quota without an exemption
+func (e *QuotaEvaluation) Below() []QuotaResult {
+	var below []QuotaResult
+	for _, r := range e.Results {
+		if !r.Met() {
+			below = append(below, r)
+		}
+	}
+	return below
+}
+
+// Exempt returns the members excused by a leave of absence
+func (e *QuotaEvaluation) Exempt() []QuotaResult {
+	var exempt []QuotaResult
+	for _, r := range e.Results {
+		if r.Exempt {
+			exempt = append(exempt, r)
+		}
+	}
+	return exempt
 }
@@ -0,0 +1,218 @@
+package faction
+
+import (
+	"kaizen-hq/internal/permission"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPositionPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		flags    Position
+		want     []string
+	}{
+		{
+			name:     "no flags",
+			position: "Member",
+			want:     nil,
+		},
+		{
+			name:     "api access",
+			position: "Officer",
+			flags:    Position{CanAccessFactionAPI: 1},
+			want:     []string{permission.ViewLogs},
+		},
+		{
+			name:     "banker",
+			position: "Banker",
+			flags:    Position{CanGiveMoney: 1, CanGiveItem: 1},
+			want:     []string{permission.BankerFulfill},
+		},
+		{
+			name:     "recruiter",
+			position: "Recruiter",
+			flags:    Position{CanManageApplications: 1, CanAccessFactionAPI: 1},
+			want:     []string{permission.ViewLogs, permission.VerifyAll},
+		},
+		{
+			name:     "every flag without leadership",
+			position: "Council",
+			flags:    leadershipPosition,
+			want:     []string{permission.ViewLogs, permission.BankerFulfill, permission.VerifyAll},
+		},
+		{
+			name:     "leader",
+			position: PositionLeader,
+			flags:    leadershipPosition,
+			want: []string{
+				permission.ViewLogs, permission.BankerFulfill, permission.VerifyAll,
+				permission.ConfigEdit, permission.RolesManage,
+			},
+		},
+		{
+			name:     "co-leader without flags",
+			position: PositionCoLeader,
+			want:     []string{permission.ConfigEdit, permission.RolesManage},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PositionPermissions(tt.position, &tt.flags)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("PositionPermissions(%q) = %v, want %v", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	end := time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		period string
+		want   time.Time
+		wantOK bool
+	}{
+		{PeriodDay, time.Date(2024, time.March, 30, 12, 0, 0, 0, time.UTC), true},
+		{PeriodWeek, time.Date(2024, time.March, 24, 12, 0, 0, 0, time.UTC), true},
+		// AddDate normalises February 31st to March 2nd
+		{PeriodMonth, time.Date(2024, time.March, 2, 12, 0, 0, 0, time.UTC), true},
+		{"year", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := PeriodStart(tt.period, end)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("PeriodStart(%q) = %v, %v, want %v, %v", tt.period, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGymGain(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end UserGymEnergy
+		want       GymGain
+	}{
+		{
+			name:  "no training",
+			start: UserGymEnergy{Strength: 100, Speed: 100, Defense: 100, Dexterity: 100},
+			end:   UserGymEnergy{Strength: 100, Speed: 100, Defense: 100, Dexterity: 100},
+			want:  GymGain{},
+		},
+		{
+			name:  "trained",
+			start: UserGymEnergy{Strength: 100, Speed: 200, Defense: 300, Dexterity: 400},
+			end:   UserGymEnergy{Strength: 150, Speed: 200, Defense: 310, Dexterity: 500},
+			want:  GymGain{Strength: 50, Defense: 10, Dexterity: 100, Total: 160},
+		},
+		{
+			name:  "rejoined resets counters",
+			start: UserGymEnergy{Strength: 5000, Speed: 5000, Defense: 100, Dexterity: 100},
+			end:   UserGymEnergy{Strength: 30, Speed: 0, Defense: 120, Dexterity: 100},
+			want:  GymGain{Strength: 30, Defense: 20, Total: 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gymGain(&tt.start, &tt.end); got != tt.want {
+				t.Errorf("gymGain() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGymLeaderboardIdle(t *testing.T) {
+	entries := []GymGain{
+		{TornID: 1, Strength: 100, Total: 100},
+		{TornID: 2, Speed: 50, Total: 50},
+		{TornID: 3},
+	}
+
+	tests := []struct {
+		stat string
+		want []int
+	}{
+		{StatTotal, []int{3}},
+		{StatStrength, []int{2, 3}},
+		{StatSpeed, []int{1, 3}},
+		{StatDefense, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		board := GymLeaderboard{Stat: tt.stat, Entries: entries}
+
+		var got []int
+		for _, e := range board.Idle() {
+			got = append(got, e.TornID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Idle() for %s = %v, want %v", tt.stat, got, tt.want)
+		}
+	}
+}
+
+func TestLeaveOfAbsenceOverlaps(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.May, d, 0, 0, 0, 0, time.UTC) }
+	leave := LeaveOfAbsence{StartsAt: day(10), EndsAt: day(15)}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     bool
+	}{
+		{"before", day(1), day(8), false},
+		{"ends as leave starts", day(3), day(10), false},
+		{"overlaps start", day(8), day(12), true},
+		{"inside", day(11), day(12), true},
+		{"covers", day(1), day(30), true},
+		{"overlaps end", day(14), day(20), true},
+		{"starts as leave ends", day(15), day(22), false},
+		{"after", day(20), day(27), false},
+	}
+
+	for _, tt := range tests {
+		if got := leave.Overlaps(tt.from, tt.to); got != tt.want {
+			t.Errorf("%s: Overlaps() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuotaEvaluation(t *testing.T) {
+	eval := QuotaEvaluation{Results: []QuotaResult{
+		{GymGain: GymGain{TornID: 1, Total: 500}, Required: 500},
+		{GymGain: GymGain{TornID: 2, Total: 499}, Required: 500},
+		{GymGain: GymGain{TornID: 3, Total: 0}, Required: 500, Exempt: true},
+		{GymGain: GymGain{TornID: 4, Total: 900}, Required: 500, Exempt: true},
+		{GymGain: GymGain{TornID: 5, Total: 0}, Required: 0},
+	}}
+
+	ids := func(results []QuotaResult) []int {
+		var ids []int
+		for _, r := range results {
+			ids = append(ids, r.TornID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name string
+		got  []QuotaResult
+		want []int
+	}{
+		{"below", eval.Below(), []int{2}},
+		{"exempt", eval.Exempt(), []int{3, 4}},
+	}
+
+	for _, tt := range tests {
+		if got := ids(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
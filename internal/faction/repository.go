@@ -2,11 +2,20 @@ package faction
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrNoGymSnapshots = errors.New("no gym energy snapshots recorded")
+	ErrQuotaNotFound  = errors.New("gym quota not found")
+	ErrLeaveNotFound  = errors.New("leave of absence not found")
+)
+
 type Repository struct {
 	db *pgxpool.Pool
 }
@@ -15,10 +24,13 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
+// SaveContributors stores a gym energy snapshot atomically in one round trip.
+// It returns how many rows were inserted and how many were skipped because
+// the member already had a snapshot for that day.
 func (r *Repository) SaveContributors(
 	ctx context.Context,
 	userEnergy []UserGymEnergy,
-) error {
+) (inserted, skipped int, err error) {
 	const query = `
 	INSERT INTO user_gym_energy_log
 		(torn_id, strength, speed, defense, dexterity, total, timestamp)
@@ -26,9 +38,15 @@ func (r *Repository) SaveContributors(
 	ON CONFLICT (torn_id, timestamp) DO NOTHING
 `
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
 	for _, s := range userEnergy {
-		_, err := r.db.Exec(
-			ctx,
+		batch.Queue(
 			query,
 			s.UserID,
 			s.Strength,
@@ -38,9 +56,306 @@ func (r *Repository) SaveContributors(
 			s.Total,
 			s.Timestamp,
 		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for _, s := range userEnergy {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, 0, fmt.Errorf("insert failed for user %s : %w", s.UserID, err)
+		}
+		if tag.RowsAffected() == 0 {
+			skipped++
+		} else {
+			inserted++
+		}
+	}
+	if err := results.Close(); err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	return inserted, skipped, nil
+}
+
+// LatestSnapshot returns the member's most recent gym energy snapshot taken at or before the given time
+func (r *Repository) LatestSnapshot(ctx context.Context, tornID string, before time.Time) (*UserGymEnergy, error) {
+	query := `SELECT torn_id, strength, speed, defense, dexterity, total, timestamp
+	FROM user_gym_energy_log
+	WHERE torn_id = $1 AND timestamp <= $2
+	ORDER BY timestamp DESC
+	LIMIT 1`
+
+	return scanSnapshot(r.db.QueryRow(ctx, query, tornID, before))
+}
+
+// EarliestSnapshot returns the member's first gym energy snapshot taken at or after the given time
+func (r *Repository) EarliestSnapshot(ctx context.Context, tornID string, after time.Time) (*UserGymEnergy, error) {
+	query := `SELECT torn_id, strength, speed, defense, dexterity, total, timestamp
+	FROM user_gym_energy_log
+	WHERE torn_id = $1 AND timestamp >= $2
+	ORDER BY timestamp
+	LIMIT 1`
+
+	return scanSnapshot(r.db.QueryRow(ctx, query, tornID, after))
+}
+
+// LatestSnapshots returns each member's most recent snapshot taken at or before the given time
+func (r *Repository) LatestSnapshots(ctx context.Context, before time.Time) ([]UserGymEnergy, error) {
+	query := `SELECT DISTINCT ON (torn_id) torn_id, strength, speed, defense, dexterity, total, timestamp
+	FROM user_gym_energy_log
+	WHERE timestamp <= $1
+	ORDER BY torn_id, timestamp DESC`
+
+	return r.listSnapshots(ctx, query, before)
+}
+
+// EarliestSnapshots returns each member's first snapshot taken within the given range
+func (r *Repository) EarliestSnapshots(ctx context.Context, after, before time.Time) ([]UserGymEnergy, error) {
+	query := `SELECT DISTINCT ON (torn_id) torn_id, strength, speed, defense, dexterity, total, timestamp
+	FROM user_gym_energy_log
+	WHERE timestamp >= $1 AND timestamp <= $2
+	ORDER BY torn_id, timestamp`
+
+	return r.listSnapshots(ctx, query, after, before)
+}
+
+// MemberSnapshots returns a member's snapshots within the given range, oldest first
+func (r *Repository) MemberSnapshots(ctx context.Context, tornID string, after, before time.Time) ([]UserGymEnergy, error) {
+	query := `SELECT torn_id, strength, speed, defense, dexterity, total, timestamp
+	FROM user_gym_energy_log
+	WHERE torn_id = $1 AND timestamp >= $2 AND timestamp <= $3
+	ORDER BY timestamp`
+
+	return r.listSnapshots(ctx, query, tornID, after, before)
+}
+
+func (r *Repository) listSnapshots(ctx context.Context, query string, args ...any) ([]UserGymEnergy, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []UserGymEnergy
+	for rows.Next() {
+		s, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *s)
+	}
+
+	return snapshots, rows.Err()
+}
+
+func scanSnapshot(row pgx.Row) (*UserGymEnergy, error) {
+	s := &UserGymEnergy{}
+
+	err := row.Scan(&s.UserID, &s.Strength, &s.Speed, &s.Defense, &s.Dexterity, &s.Total, &s.Timestamp)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoGymSnapshots
+		}
+		return nil, err
+	}
+
+	return s, nil
+}
+
+const memberColumns = `torn_id, name, level, days_in_faction, position, last_action_status,
+	last_action_at, status, status_description, in_oc, updated_at`
+
+// ListMembers returns the stored faction roster ordered by name
+func (r *Repository) ListMembers(ctx context.Context) ([]Member, error) {
+	query := `SELECT ` + memberColumns + ` FROM faction_members ORDER BY name`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Member])
+}
+
+// SaveRoster replaces the stored roster with the given members, recording
+// everyone who appeared or disappeared since the last save. Nothing is
+// recorded when the roster was empty, so the first sync isn't one big join.
+func (r *Repository) SaveRoster(ctx context.Context, members []Member) (joined, left []MemberEvent, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT torn_id, name FROM faction_members`)
+	if err != nil {
+		return nil, nil, err
+	}
+	previous := map[int]string{}
+	var (
+		tornID int
+		name   string
+	)
+	_, err = pgx.ForEachRow(rows, []any{&tornID, &name}, func() error {
+		previous[tornID] = name
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	upsert := `INSERT INTO faction_members (` + memberColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (torn_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			level = EXCLUDED.level,
+			days_in_faction = EXCLUDED.days_in_faction,
+			position = EXCLUDED.position,
+			last_action_status = EXCLUDED.last_action_status,
+			last_action_at = EXCLUDED.last_action_at,
+			status = EXCLUDED.status,
+			status_description = EXCLUDED.status_description,
+			in_oc = EXCLUDED.in_oc,
+			updated_at = EXCLUDED.updated_at`
+
+	current := map[int]bool{}
+	for _, m := range members {
+		_, err := tx.Exec(ctx, upsert,
+			m.TornID, m.Name, m.Level, m.DaysInFaction, m.Position, m.LastActionStatus,
+			m.LastActionAt, m.Status, m.StatusDescription, m.InOC, m.UpdatedAt,
+		)
 		if err != nil {
-			return fmt.Errorf("insert failed for user %s : %w", s.UserID, err)
+			return nil, nil, fmt.Errorf("upsert failed for member %d: %w", m.TornID, err)
+		}
+		current[m.TornID] = true
+
+		if _, ok := previous[m.TornID]; !ok && len(previous) > 0 {
+			joined = append(joined, MemberEvent{TornID: m.TornID, Name: m.Name, Type: MemberJoined})
+		}
+	}
+
+	for id, name := range previous {
+		if current[id] {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM faction_members WHERE torn_id = $1`, id); err != nil {
+			return nil, nil, err
 		}
+		left = append(left, MemberEvent{TornID: id, Name: name, Type: MemberLeft})
+	}
+
+	for _, events := range [][]MemberEvent{joined, left} {
+		for i := range events {
+			e := &events[i]
+			err := tx.QueryRow(ctx,
+				`INSERT INTO faction_member_events (torn_id, name, type) VALUES ($1, $2, $3) RETURNING id, created_at`,
+				e.TornID, e.Name, e.Type,
+			).Scan(&e.ID, &e.CreatedAt)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return joined, left, tx.Commit(ctx)
+}
+
+// MemberEvents returns the joins and departures recorded since the given time, newest first
+func (r *Repository) MemberEvents(ctx context.Context, since time.Time) ([]MemberEvent, error) {
+	query := `SELECT id, torn_id, name, type, created_at
+		FROM faction_member_events
+		WHERE created_at >= $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[MemberEvent])
+}
+
+// ListQuotas returns every gym quota, the global one first
+func (r *Repository) ListQuotas(ctx context.Context) ([]GymQuota, error) {
+	query := `SELECT id, role_id, weekly_energy, updated_at FROM gym_quotas ORDER BY role_id NULLS FIRST`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[GymQuota])
+}
+
+// SetQuota creates or replaces the quota of a role, or the global quota when roleID is nil
+func (r *Repository) SetQuota(ctx context.Context, roleID *int, weeklyEnergy int) (*GymQuota, error) {
+	quota := &GymQuota{RoleID: roleID, WeeklyEnergy: weeklyEnergy}
+
+	// The conflict target matches gym_quotas_role_id_key, which folds the global quota onto 0
+	query := `INSERT INTO gym_quotas (role_id, weekly_energy) VALUES ($1, $2)
+		ON CONFLICT ((COALESCE(role_id, 0)))
+		DO UPDATE SET weekly_energy = EXCLUDED.weekly_energy, updated_at = now()
+		RETURNING id, updated_at`
+
+	err := r.db.QueryRow(ctx, query, roleID, weeklyEnergy).Scan(&quota.ID, &quota.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return quota, nil
+}
+
+func (r *Repository) DeleteQuota(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM gym_quotas WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrQuotaNotFound
+	}
+
+	return nil
+}
+
+// ListLeaves returns the leaves of absence that end after the given time, soonest first
+func (r *Repository) ListLeaves(ctx context.Context, endingAfter time.Time) ([]LeaveOfAbsence, error) {
+	query := `SELECT id, torn_id, starts_at, ends_at, reason, created_by, created_at
+		FROM leaves_of_absence
+		WHERE ends_at > $1
+		ORDER BY starts_at`
+
+	rows, err := r.db.Query(ctx, query, endingAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[LeaveOfAbsence])
+}
+
+func (r *Repository) CreateLeave(ctx context.Context, leave *LeaveOfAbsence) (*LeaveOfAbsence, error) {
+	query := `INSERT INTO leaves_of_absence (torn_id, starts_at, ends_at, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(ctx, query,
+		leave.TornID, leave.StartsAt, leave.EndsAt, leave.Reason, leave.CreatedBy,
+	).Scan(&leave.ID, &leave.CreatedAt)
+
+	return leave, err
+}
+
+func (r *Repository) DeleteLeave(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM leaves_of_absence WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLeaveNotFound
 	}
 
 	return nil
@@ -1,27 +1,61 @@
 package faction
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
 	"kaizen-hq/config"
+	"kaizen-hq/internal/account"
 	"kaizen-hq/internal/client"
+	"kaizen-hq/internal/role"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
+var (
+	ErrInvalidLeave         = errors.New("leave of absence must end after it starts")
+	ErrInvalidCrimeCategory = errors.New("crime category must be one of " + strings.Join(client.CrimeCategories, ", "))
+)
+
 type Service struct {
-	repo       *Repository
-	config     *config.Config
-	tornClient client.Client
+	repo           *Repository
+	config         *config.Config
+	tornClient     client.Client
+	accountService *account.Service
+	roleService    *role.Service
 }
 
-func NewService(repo *Repository, cfg *config.Config, tornClient client.Client) *Service {
-	return &Service{repo: repo, config: cfg, tornClient: tornClient}
+func NewService(
+	repo *Repository,
+	cfg *config.Config,
+	tornClient client.Client,
+	accountService *account.Service,
+	roleService *role.Service,
+) *Service {
+	return &Service{
+		repo:           repo,
+		config:         cfg,
+		tornClient:     tornClient,
+		accountService: accountService,
+		roleService:    roleService,
+	}
 }
 
+// MergeAndSaveGymEnergy combines the per-stat contributor lists into one
+// snapshot per member and saves it, returning how many rows were inserted and
+// how many already existed. Snapshots are stamped with the start of the UTC
+// day, so running it again the same day doesn't record a second snapshot.
 func (s *Service) MergeAndSaveGymEnergy(
+	ctx context.Context,
 	strengthData, speedData, defenseData, dexterityData client.StatMap,
-) error {
+) (inserted, skipped int, err error) {
 	userStats := map[string]*UserGymEnergy{}
-	now := time.Now()
+	now := time.Now().UTC().Truncate(24 * time.Hour)
 
 	merge := func(data client.StatMap, field string) {
 		for _, users := range data {
@@ -56,12 +90,10 @@ func (s *Service) MergeAndSaveGymEnergy(
 		energyList = append(energyList, *entry)
 	}
 
-	return s.repo.SaveContributors(context.Background(), energyList)
+	return s.repo.SaveContributors(ctx, energyList)
 }
 
-func (s *Service) UpdateGymEnergy(apiKey string) error {
-	ctx := context.Background()
-
+func (s *Service) UpdateGymEnergy(ctx context.Context, apiKey string) error {
 	strengthData, err := s.tornClient.FetchGymEnergy(ctx, apiKey, "gymstrength")
 	if err != nil {
 		return err
@@ -82,5 +114,466 @@ func (s *Service) UpdateGymEnergy(apiKey string) error {
 		return err
 	}
 
-	return s.MergeAndSaveGymEnergy(strengthData, speedData, defenseData, dexterityData)
+	inserted, skipped, err := s.MergeAndSaveGymEnergy(ctx, strengthData, speedData, defenseData, dexterityData)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Saved gym energy snapshot: %d inserted, %d already recorded", inserted, skipped)
+	return nil
+}
+
+// SnapshotGymEnergy records today's gym energy contributions using pooled faction keys
+func (s *Service) SnapshotGymEnergy(ctx context.Context) error {
+	if err := s.UpdateGymEnergy(ctx, client.PooledKey); err != nil {
+		return fmt.Errorf("failed to snapshot gym energy: %w", err)
+	}
+
+	return nil
+}
+
+// GymSummary reports how much energy a member trained over the last given number of days
+func (s *Service) GymSummary(ctx context.Context, tornID int, days int) (*GymSummary, error) {
+	id := strconv.Itoa(tornID)
+	now := time.Now()
+
+	latest, err := s.repo.LatestSnapshot(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fall back to the first snapshot when we haven't been recording for the full period
+	start, err := s.repo.LatestSnapshot(ctx, id, now.AddDate(0, 0, -days))
+	if errors.Is(err, ErrNoGymSnapshots) {
+		start, err = s.repo.EarliestSnapshot(ctx, id, now.AddDate(0, 0, -days))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &GymSummary{
+		Days:      max(1, int(latest.Timestamp.Sub(start.Timestamp).Hours()/24)),
+		Strength:  latest.Strength - start.Strength,
+		Speed:     latest.Speed - start.Speed,
+		Defense:   latest.Defense - start.Defense,
+		Dexterity: latest.Dexterity - start.Dexterity,
+		Total:     latest.Total - start.Total,
+	}, nil
+}
+
+// GymLeaderboard ranks members by the energy they trained into a stat between
+// two times. When a roster has been synced only current members are ranked,
+// including those with no snapshots who trained nothing.
+func (s *Service) GymLeaderboard(ctx context.Context, from, to time.Time, stat string) (*GymLeaderboard, error) {
+	ends, err := s.repo.LatestSnapshots(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	starts, err := s.repo.LatestSnapshots(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// Members whose recording began during the period are measured from their first snapshot
+	firsts, err := s.repo.EarliestSnapshots(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	startByID := map[string]*UserGymEnergy{}
+	for i := range firsts {
+		startByID[firsts[i].UserID] = &firsts[i]
+	}
+	for i := range starts {
+		startByID[starts[i].UserID] = &starts[i]
+	}
+
+	members, err := s.repo.ListMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := map[int]string{}
+	for _, m := range members {
+		names[m.TornID] = m.Name
+	}
+
+	gains := map[int]GymGain{}
+	for i := range ends {
+		end := &ends[i]
+		tornID, err := strconv.Atoi(end.UserID)
+		if err != nil {
+			continue
+		}
+		if _, ok := names[tornID]; !ok && len(names) > 0 {
+			continue
+		}
+
+		start, ok := startByID[end.UserID]
+		if !ok {
+			start = end
+		}
+
+		gain := gymGain(start, end)
+		gain.TornID = tornID
+		gain.Name = names[tornID]
+		gains[tornID] = gain
+	}
+
+	for tornID, name := range names {
+		if _, ok := gains[tornID]; !ok {
+			gains[tornID] = GymGain{TornID: tornID, Name: name}
+		}
+	}
+
+	board := &GymLeaderboard{From: from, To: to, Stat: stat, Entries: make([]GymGain, 0, len(gains))}
+	for _, gain := range gains {
+		board.Entries = append(board.Entries, gain)
+	}
+	slices.SortFunc(board.Entries, func(a, b GymGain) int {
+		if c := cmp.Compare(b.Stat(stat), a.Stat(stat)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.TornID, b.TornID)
+	})
+
+	return board, nil
+}
+
+// DailyGymGains returns the energy a member trained on each day between two times
+func (s *Service) DailyGymGains(ctx context.Context, tornID int, from, to time.Time) ([]DailyGymGain, error) {
+	// The snapshot from the day before is the baseline for the first day
+	snapshots, err := s.repo.MemberSnapshots(ctx, strconv.Itoa(tornID), from.AddDate(0, 0, -1), to)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []DailyGymGain{}
+	for i := 1; i < len(snapshots); i++ {
+		gain := gymGain(&snapshots[i-1], &snapshots[i])
+		gain.TornID = tornID
+		days = append(days, DailyGymGain{Date: snapshots[i].Timestamp, GymGain: gain})
+	}
+
+	return days, nil
+}
+
+// SyncPositions mirrors each Torn faction position onto a role whose
+// permissions follow the position's flags, and gives every registered
+// member the role of the position they hold in game
+func (s *Service) SyncPositions(ctx context.Context) (*PositionSync, error) {
+	positions, basic, err := s.fetchPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	positions[PositionLeader] = leadershipPosition
+	positions[PositionCoLeader] = leadershipPosition
+
+	result := &PositionSync{}
+
+	// Position name -> ID of the role mirroring it
+	roleIDs := map[string]int{}
+	for name, position := range positions {
+		r, err := s.roleService.Ensure(ctx, &role.Role{
+			Name:         PositionRoleName(name),
+			Description:  fmt.Sprintf("Holds the %s position in the faction", name),
+			IsLeadership: position.CanAccessFactionAPI == 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to sync role for position %s: %w", name, err)
+		}
+
+		if err := s.roleService.SetPermissions(ctx, r.ID, PositionPermissions(name, &position)); err != nil {
+			return nil, fmt.Errorf("failed to sync permissions for position %s: %w", name, err)
+		}
+
+		roleIDs[name] = r.ID
+		result.Roles++
+	}
+
+	// Torn ID -> ID of the role the member should hold
+	wanted := map[int]int{}
+	for id, member := range basic.Members {
+		tornID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		if roleID, ok := roleIDs[member.Position]; ok {
+			wanted[tornID] = roleID
+		}
+	}
+
+	// Take position roles away from anyone who no longer holds the position
+	roles, err := s.roleService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	held := map[int]bool{}
+	for _, r := range roles {
+		if !strings.HasPrefix(r.Name, PositionRolePrefix) {
+			continue
+		}
+
+		members, err := s.roleService.ListMembers(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range members {
+			if wanted[m.TornID] == r.ID {
+				held[m.TornID] = true
+				continue
+			}
+			if err := s.roleService.RemoveMember(ctx, r.ID, m.TornID); err != nil {
+				return nil, err
+			}
+			result.Removed++
+		}
+	}
+
+	for tornID, roleID := range wanted {
+		if held[tornID] {
+			continue
+		}
+
+		err := s.roleService.AddMember(ctx, roleID, tornID)
+		if errors.Is(err, account.ErrUserNotFound) {
+			// Members without an account pick up their role once they register
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Assigned++
+	}
+
+	return result, nil
+}
+
+// fetchPositions loads the faction's positions and member list
+func (s *Service) fetchPositions(ctx context.Context) (map[string]Position, *client.FactionBasic, error) {
+	positions, err := s.tornClient.FetchFactionPositions(ctx, client.PooledKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch faction positions: %w", err)
+	}
+
+	basic, err := s.tornClient.FetchFactionBasic(ctx, client.PooledKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch faction basic: %w", err)
+	}
+
+	// The client may share its cached map, and the sync adds the leadership
+	// positions to what it returns
+	positions = maps.Clone(positions)
+	if positions == nil {
+		positions = map[string]Position{}
+	}
+	return positions, basic, nil
+}
+
+// SyncMembers stores the faction's current member list, recording who joined or left.
+// It reads the v2 members selection, which also says who is in an organized crime.
+func (s *Service) SyncMembers(ctx context.Context) (*RosterSync, error) {
+	details, err := s.tornClient.FetchFactionMembers(ctx, client.PooledKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch faction members: %w", err)
+	}
+
+	now := time.Now()
+	members := make([]Member, 0, len(details))
+	for _, m := range details {
+		members = append(members, Member{
+			TornID:            m.ID,
+			Name:              m.Name,
+			Level:             m.Level,
+			DaysInFaction:     m.DaysInFaction,
+			Position:          m.Position,
+			LastActionStatus:  m.LastAction.Status,
+			LastActionAt:      time.Unix(m.LastAction.Timestamp, 0),
+			Status:            m.Status.State,
+			StatusDescription: m.Status.Description,
+			InOC:              m.IsInOC,
+			UpdatedAt:         now,
+		})
+	}
+
+	joined, left, err := s.repo.SaveRoster(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RosterSync{Members: len(members), Joined: joined, Left: left}, nil
+}
+
+// Crimes returns the faction's organized crimes in a category, or every category when it is empty
+func (s *Service) Crimes(ctx context.Context, category string) ([]client.Crime, error) {
+	if category != "" && !slices.Contains(client.CrimeCategories, category) {
+		return nil, ErrInvalidCrimeCategory
+	}
+
+	crimes, err := s.tornClient.FetchFactionCrimes(ctx, client.PooledKey, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch faction crimes: %w", err)
+	}
+	return crimes, nil
+}
+
+// ListMembers returns the faction roster as of the last sync
+func (s *Service) ListMembers(ctx context.Context) ([]Member, error) {
+	return s.repo.ListMembers(ctx)
+}
+
+// MemberEvents returns the joins and departures recorded since the given time
+func (s *Service) MemberEvents(ctx context.Context, since time.Time) ([]MemberEvent, error) {
+	return s.repo.MemberEvents(ctx, since)
+}
+
+// GymQuotas returns the configured gym quotas
+func (s *Service) GymQuotas(ctx context.Context) ([]GymQuota, error) {
+	return s.repo.ListQuotas(ctx)
+}
+
+// SetGymQuota sets the weekly gym energy members of a role must train, or
+// everyone's when roleID is nil
+func (s *Service) SetGymQuota(ctx context.Context, roleID *int, weeklyEnergy int) (*GymQuota, error) {
+	if roleID != nil {
+		if _, err := s.roleService.Get(ctx, *roleID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.SetQuota(ctx, roleID, weeklyEnergy)
+}
+
+func (s *Service) DeleteGymQuota(ctx context.Context, id int) error {
+	return s.repo.DeleteQuota(ctx, id)
+}
+
+// Leaves returns the leaves of absence that haven't ended yet
+func (s *Service) Leaves(ctx context.Context) ([]LeaveOfAbsence, error) {
+	return s.repo.ListLeaves(ctx, time.Now())
+}
+
+// RecordLeave stores a leave of absence exempting a member from gym quotas
+func (s *Service) RecordLeave(ctx context.Context, leave *LeaveOfAbsence) (*LeaveOfAbsence, error) {
+	if !leave.EndsAt.After(leave.StartsAt) {
+		return nil, ErrInvalidLeave
+	}
+
+	return s.repo.CreateLeave(ctx, leave)
+}
+
+func (s *Service) DeleteLeave(ctx context.Context, id int) error {
+	return s.repo.DeleteLeave(ctx, id)
+}
+
+// EvaluateGymQuotas checks the energy every member trained between two times
+// against their quota. Members of a role with its own quota are held to it
+// (the most lenient one if they have several) instead of the global quota,
+// and members on leave at any point during the period are exempt.
+func (s *Service) EvaluateGymQuotas(ctx context.Context, from, to time.Time) (*QuotaEvaluation, error) {
+	quotas, err := s.repo.ListQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &QuotaEvaluation{From: from, To: to, Results: []QuotaResult{}}
+	if len(quotas) == 0 {
+		return evaluation, nil
+	}
+
+	board, err := s.GymLeaderboard(ctx, from, to, StatTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions := map[int]string{}
+	for _, m := range members {
+		positions[m.TornID] = PositionRoleName(m.Position)
+	}
+
+	global := -1
+	// Torn ID -> quota of the most lenient role quota the member falls under
+	roleQuotas := map[int]int{}
+	for _, q := range quotas {
+		if q.RoleID == nil {
+			global = q.WeeklyEnergy
+			continue
+		}
+
+		holders, err := s.quotaRoleHolders(ctx, *q.RoleID, positions)
+		if err != nil {
+			return nil, err
+		}
+		for tornID := range holders {
+			if current, ok := roleQuotas[tornID]; !ok || q.WeeklyEnergy < current {
+				roleQuotas[tornID] = q.WeeklyEnergy
+			}
+		}
+	}
+
+	leaves, err := s.repo.ListLeaves(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	onLeave := map[int]bool{}
+	for _, l := range leaves {
+		if l.Overlaps(from, to) {
+			onLeave[l.TornID] = true
+		}
+	}
+
+	for _, gain := range board.Entries {
+		required, ok := roleQuotas[gain.TornID]
+		if !ok {
+			if global < 0 {
+				continue
+			}
+			required = global
+		}
+
+		evaluation.Results = append(evaluation.Results, QuotaResult{
+			GymGain:  gain,
+			Required: required,
+			Exempt:   onLeave[gain.TornID],
+		})
+	}
+
+	return evaluation, nil
+}
+
+// quotaRoleHolders returns the Torn IDs of everyone holding a role, either
+// through their account or, for position roles, the position they hold in game
+func (s *Service) quotaRoleHolders(ctx context.Context, roleID int, positions map[int]string) (map[int]bool, error) {
+	r, err := s.roleService.Get(ctx, roleID)
+	if errors.Is(err, role.ErrRoleNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holders := map[int]bool{}
+
+	accounts, err := s.roleService.ListMembers(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range accounts {
+		holders[m.TornID] = true
+	}
+
+	for tornID, positionRole := range positions {
+		if positionRole == r.Name {
+			holders[tornID] = true
+		}
+	}
+
+	return holders, nil
 }
@@ -0,0 +1,44 @@
+package guild
+
+import (
+	"slices"
+	"time"
+)
+
+// Features that can be switched on or off per guild
+const (
+	FeatureBanker  = "banker"
+	FeatureProfile = "profile"
+	FeatureVerify  = "verify"
+	FeatureFaction = "faction"
+)
+
+// AllFeatures lists every feature a guild can enable
+var AllFeatures = []string{FeatureBanker, FeatureProfile, FeatureVerify, FeatureFaction}
+
+type Settings struct {
+	GuildID         string    `json:"guild_id"`
+	BankerChannelID string    `json:"banker_channel_id"`
+	LogChannelID    string    `json:"log_channel_id"`
+	VerifiedRoleID  string    `json:"verified_role_id"`
+	EnabledFeatures []string  `json:"enabled_features"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// DefaultSettings returns the settings used for a guild that has not been configured yet
+func DefaultSettings(guildID string) *Settings {
+	return &Settings{
+		GuildID:         guildID,
+		EnabledFeatures: slices.Clone(AllFeatures),
+	}
+}
+
+// FeatureEnabled reports whether the given feature is switched on for the guild
+func (s *Settings) FeatureEnabled(feature string) bool {
+	return slices.Contains(s.EnabledFeatures, feature)
+}
+
+// IsKnownFeature reports whether the feature name is one the bot understands
+func IsKnownFeature(feature string) bool {
+	return slices.Contains(AllFeatures, feature)
+}
@@ -0,0 +1,101 @@
+package guild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrSettingsNotFound = errors.New("guild settings not found")
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+const settingsColumns = `guild_id, banker_channel_id, log_channel_id, verified_role_id, enabled_features, updated_at`
+
+func scanSettings(row pgx.Row) (*Settings, error) {
+	settings := &Settings{}
+
+	err := row.Scan(
+		&settings.GuildID,
+		&settings.BankerChannelID,
+		&settings.LogChannelID,
+		&settings.VerifiedRoleID,
+		&settings.EnabledFeatures,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSettingsNotFound
+		}
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// GetSettings finds the stored settings for a guild
+func (r *Repository) GetSettings(ctx context.Context, guildID string) (*Settings, error) {
+	query := `SELECT ` + settingsColumns + ` FROM guild_settings WHERE guild_id = $1`
+
+	return scanSettings(r.db.QueryRow(ctx, query, guildID))
+}
+
+// ListSettings returns the settings of every configured guild
+func (r *Repository) ListSettings(ctx context.Context) ([]*Settings, error) {
+	query := `SELECT ` + settingsColumns + ` FROM guild_settings`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*Settings
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, settings)
+	}
+
+	return list, rows.Err()
+}
+
+// SaveSettings inserts or replaces the settings for a guild
+func (r *Repository) SaveSettings(ctx context.Context, settings *Settings) error {
+	query := `INSERT INTO guild_settings (` + settingsColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (guild_id) DO UPDATE SET
+		banker_channel_id = EXCLUDED.banker_channel_id,
+		log_channel_id = EXCLUDED.log_channel_id,
+		verified_role_id = EXCLUDED.verified_role_id,
+		enabled_features = EXCLUDED.enabled_features,
+		updated_at = EXCLUDED.updated_at`
+
+	settings.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		settings.GuildID,
+		settings.BankerChannelID,
+		settings.LogChannelID,
+		settings.VerifiedRoleID,
+		settings.EnabledFeatures,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save guild settings: %w", err)
+	}
+
+	return nil
+}
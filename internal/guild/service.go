@@ -0,0 +1,83 @@
+package guild
+
+import (
+	"context"
+	"errors"
+	"kaizen-hq/config"
+	"slices"
+)
+
+var ErrUnknownFeature = errors.New("unknown feature")
+
+type Service struct {
+	repo   *Repository
+	config *config.Config
+}
+
+func NewService(repo *Repository, cfg *config.Config) *Service {
+	return &Service{repo: repo, config: cfg}
+}
+
+// GetSettings returns the guild's stored settings, or the defaults if it has none
+func (s *Service) GetSettings(ctx context.Context, guildID string) (*Settings, error) {
+	settings, err := s.repo.GetSettings(ctx, guildID)
+	if errors.Is(err, ErrSettingsNotFound) {
+		return DefaultSettings(guildID), nil
+	}
+
+	return settings, err
+}
+
+func (s *Service) ListSettings(ctx context.Context) ([]*Settings, error) {
+	return s.repo.ListSettings(ctx)
+}
+
+func (s *Service) SetBankerChannel(ctx context.Context, guildID, channelID string) (*Settings, error) {
+	return s.update(ctx, guildID, func(settings *Settings) {
+		settings.BankerChannelID = channelID
+	})
+}
+
+func (s *Service) SetLogChannel(ctx context.Context, guildID, channelID string) (*Settings, error) {
+	return s.update(ctx, guildID, func(settings *Settings) {
+		settings.LogChannelID = channelID
+	})
+}
+
+func (s *Service) SetVerifiedRole(ctx context.Context, guildID, roleID string) (*Settings, error) {
+	return s.update(ctx, guildID, func(settings *Settings) {
+		settings.VerifiedRoleID = roleID
+	})
+}
+
+// SetFeature switches a feature on or off for the guild
+func (s *Service) SetFeature(ctx context.Context, guildID, feature string, enabled bool) (*Settings, error) {
+	if !IsKnownFeature(feature) {
+		return nil, ErrUnknownFeature
+	}
+
+	return s.update(ctx, guildID, func(settings *Settings) {
+		settings.EnabledFeatures = slices.DeleteFunc(settings.EnabledFeatures, func(f string) bool {
+			return f == feature
+		})
+		if enabled {
+			settings.EnabledFeatures = append(settings.EnabledFeatures, feature)
+		}
+	})
+}
+
+// update loads the guild's settings, applies the change and stores the result
+func (s *Service) update(ctx context.Context, guildID string, apply func(*Settings)) (*Settings, error) {
+	settings, err := s.GetSettings(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	apply(settings)
+
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
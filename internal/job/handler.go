@@ -0,0 +1,40 @@
+package job
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRunLimit is how many runs are returned when no limit is given
+const defaultRunLimit = 20
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListRuns returns the latest runs of a scheduled job, newest first
+func (h *Handler) ListRuns(c *gin.Context) {
+	limit := defaultRunLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive whole number"})
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.service.ListRuns(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
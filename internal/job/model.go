@@ -0,0 +1,22 @@
+package job
+
+import "time"
+
+// Status is the outcome of a scheduled job run
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Run records a single execution of a scheduled job
+type Run struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Status     Status     `json:"status"`
+	Error      string     `json:"error"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
@@ -0,0 +1,49 @@
+package job
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// StartRun records that a job has started running
+func (r *Repository) StartRun(ctx context.Context, name string) (*Run, error) {
+	run := &Run{Name: name, Status: StatusRunning}
+
+	query := `INSERT INTO job_runs (name, status) VALUES ($1, $2) RETURNING id, started_at`
+	err := r.db.QueryRow(ctx, query, run.Name, run.Status).Scan(&run.ID, &run.StartedAt)
+
+	return run, err
+}
+
+// FinishRun stores the outcome of a run
+func (r *Repository) FinishRun(ctx context.Context, run *Run) error {
+	query := `UPDATE job_runs SET status = $1, error = $2, finished_at = now() WHERE id = $3 RETURNING finished_at`
+
+	return r.db.QueryRow(ctx, query, run.Status, run.Error, run.ID).Scan(&run.FinishedAt)
+}
+
+// ListRuns returns the most recent runs of a job, newest first
+func (r *Repository) ListRuns(ctx context.Context, name string, limit int) ([]Run, error) {
+	query := `SELECT id, name, status, error, started_at, finished_at
+		FROM job_runs
+		WHERE name = $1
+		ORDER BY started_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, name, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Run])
+}
@@ -0,0 +1,46 @@
+package job
+
+import (
+	"context"
+	"kaizen-hq/config"
+	"log"
+)
+
+type Service struct {
+	repo   *Repository
+	config *config.Config
+}
+
+func NewService(repo *Repository, cfg *config.Config) *Service {
+	return &Service{repo: repo, config: cfg}
+}
+
+// Track runs fn as the named job, recording when it ran and whether it succeeded
+func (s *Service) Track(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	run, err := s.repo.StartRun(ctx, name)
+	if err != nil {
+		// Failing to record the run shouldn't stop the job itself
+		log.Printf("Error recording start of job %s: %v", name, err)
+		return fn(ctx)
+	}
+
+	jobErr := fn(ctx)
+
+	run.Status = StatusSucceeded
+	if jobErr != nil {
+		run.Status = StatusFailed
+		run.Error = jobErr.Error()
+	}
+
+	// The job's context may have been cancelled, which shouldn't lose the outcome
+	if err := s.repo.FinishRun(context.WithoutCancel(ctx), run); err != nil {
+		log.Printf("Error recording outcome of job %s: %v", name, err)
+	}
+
+	return jobErr
+}
+
+// ListRuns returns the most recent runs of a job
+func (s *Service) ListRuns(ctx context.Context, name string, limit int) ([]Run, error) {
+	return s.repo.ListRuns(ctx, name, limit)
+}
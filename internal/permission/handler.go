@@ -0,0 +1,115 @@
+package permission
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// handleError maps service errors to HTTP responses
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPermissionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrPermissionExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// permissionID reads the permissionID path parameter, responding with 400 if it isn't a number
+func permissionID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("permissionID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "permissionID must be a whole number"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) List(c *gin.Context) {
+	perms, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"permissions": perms})
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	id, ok := permissionID(c)
+	if !ok {
+		return
+	}
+
+	perm, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"permission": perm})
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req PermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	perm, err := h.service.Create(c.Request.Context(), &Permission{Name: req.Name, Description: req.Description})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"permission": perm})
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := permissionID(c)
+	if !ok {
+		return
+	}
+
+	var req PermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	perm := &Permission{ID: id, Name: req.Name, Description: req.Description}
+	if err := h.service.Update(c.Request.Context(), perm); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"permission": perm})
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	id, ok := permissionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
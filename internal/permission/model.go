@@ -1,7 +1,56 @@
 package permission
 
+import "slices"
+
+// Names of the permissions the application checks
+const (
+	ViewLogs      = "view_logs"
+	BankerFulfill = "banker.fulfill"
+	ConfigEdit    = "config.edit"
+	VerifyAll     = "verify.all"
+	RolesManage   = "roles.manage"
+	GymManage     = "gym.manage"
+)
+
 type Permission struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
+
+// Catalog describes every permission the application checks. It is
+// reconciled with the database at startup, so a feature ships its
+// permissions by adding them here.
+var Catalog = []Permission{
+	{Name: ViewLogs, Description: "Able to view logs"},
+	{Name: BankerFulfill, Description: "Able to handle banker requests"},
+	{Name: ConfigEdit, Description: "Able to change the bot configuration of a server"},
+	{Name: VerifyAll, Description: "Able to verify every member of a server at once"},
+	{Name: RolesManage, Description: "Able to manage roles, permissions and role members"},
+	{Name: GymManage, Description: "Able to manage gym quotas and leaves of absence"},
+}
+
+// CatalogNames returns the names of every permission in the catalog
+func CatalogNames() []string {
+	names := make([]string, 0, len(Catalog))
+	for _, p := range Catalog {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+type PermissionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// Grants are the roles an account holds and the permissions those roles give it
+type Grants struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// Has reports whether the grants include the named permission
+func (g *Grants) Has(name string) bool {
+	return slices.Contains(g.Permissions, name)
+}
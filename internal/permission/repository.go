@@ -8,6 +8,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var ErrPermissionNotFound = errors.New("permission not found")
+
 type Repository struct {
 	db *pgxpool.Pool
 }
@@ -24,18 +26,126 @@ func (r *Repository) CreatePermission(ctx context.Context, permission *Permissio
 	return permission, err
 }
 
-func (r *Repository) GetPermissionByName(ctx context.Context, name string) (*Permission, error) {
-	permission := &Permission{}
+const permissionColumns = `id, name, description`
 
-	query := `SELECT * FROM permissions WHERE name = $1`
-	err := r.db.QueryRow(ctx, query, name).Scan(permission)
+func scanPermission(row pgx.Row) (*Permission, error) {
+	permission := &Permission{}
 
+	err := row.Scan(&permission.ID, &permission.Name, &permission.Description)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("no permission with that name found")
+			return nil, ErrPermissionNotFound
 		}
 		return nil, err
 	}
 
 	return permission, nil
 }
+
+func (r *Repository) GetPermissionByName(ctx context.Context, name string) (*Permission, error) {
+	query := `SELECT ` + permissionColumns + ` FROM permissions WHERE name = $1`
+
+	return scanPermission(r.db.QueryRow(ctx, query, name))
+}
+
+func (r *Repository) GetPermissionByID(ctx context.Context, id int) (*Permission, error) {
+	query := `SELECT ` + permissionColumns + ` FROM permissions WHERE id = $1`
+
+	return scanPermission(r.db.QueryRow(ctx, query, id))
+}
+
+// ListPermissions returns every permission ordered by name
+func (r *Repository) ListPermissions(ctx context.Context) ([]Permission, error) {
+	query := `SELECT ` + permissionColumns + ` FROM permissions ORDER BY name`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Permission])
+}
+
+func (r *Repository) UpdatePermission(ctx context.Context, permission *Permission) error {
+	query := `UPDATE permissions SET name = $1, description = $2 WHERE id = $3`
+
+	tag, err := r.db.Exec(ctx, query, permission.Name, permission.Description, permission.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPermissionNotFound
+	}
+
+	return nil
+}
+
+// DeletePermission removes a permission and takes it away from every role
+func (r *Repository) DeletePermission(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM permissions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPermissionNotFound
+	}
+
+	return nil
+}
+
+// AccountHasPermission reports whether any of the account's roles grants the named permission
+func (r *Repository) AccountHasPermission(ctx context.Context, accountID int, name string) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1
+		FROM user_roles ur
+		JOIN role_permissions rp ON rp.role_id = ur.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE ur.user_id = $1 AND p.name = $2
+	)`
+
+	var ok bool
+	err := r.db.QueryRow(ctx, query, accountID, name).Scan(&ok)
+	return ok, err
+}
+
+// GrantsForTornID loads the roles and permissions of the account with the given Torn ID
+func (r *Repository) GrantsForTornID(ctx context.Context, tornID int) (*Grants, error) {
+	grants := &Grants{Roles: []string{}, Permissions: []string{}}
+
+	rolesQuery := `SELECT ro.name
+		FROM accounts a
+		JOIN user_roles ur ON ur.user_id = a.id
+		JOIN roles ro ON ro.id = ur.role_id
+		WHERE a.torn_id = $1
+		ORDER BY ro.name`
+
+	rows, err := r.db.Query(ctx, rolesQuery, tornID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	grants.Roles = append(grants.Roles, roles...)
+
+	permissionsQuery := `SELECT DISTINCT p.name
+		FROM accounts a
+		JOIN user_roles ur ON ur.user_id = a.id
+		JOIN role_permissions rp ON rp.role_id = ur.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE a.torn_id = $1
+		ORDER BY p.name`
+
+	rows, err = r.db.Query(ctx, permissionsQuery, tornID)
+	if err != nil {
+		return nil, err
+	}
+	perms, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	grants.Permissions = append(grants.Permissions, perms...)
+
+	return grants, nil
+}
@@ -6,6 +6,8 @@ import (
 	"kaizen-hq/config"
 )
 
+var ErrPermissionExists = errors.New("permission already exists")
+
 type Service struct {
 	repo   *Repository
 	config *config.Config
@@ -19,7 +21,43 @@ func (s *Service) Create(ctx context.Context, permission *Permission) (*Permissi
 	// Check if the permission already exists
 	_, err := s.repo.GetPermissionByName(ctx, permission.Name)
 	if err == nil {
-		return nil, errors.New("permission already exists")
+		return nil, ErrPermissionExists
 	}
 	return s.repo.CreatePermission(ctx, permission)
 }
+
+func (s *Service) Get(ctx context.Context, id int) (*Permission, error) {
+	return s.repo.GetPermissionByID(ctx, id)
+}
+
+func (s *Service) GetByName(ctx context.Context, name string) (*Permission, error) {
+	return s.repo.GetPermissionByName(ctx, name)
+}
+
+func (s *Service) List(ctx context.Context) ([]Permission, error) {
+	return s.repo.ListPermissions(ctx)
+}
+
+// Update changes a permission's details, refusing to take another permission's name
+func (s *Service) Update(ctx context.Context, permission *Permission) error {
+	existing, err := s.repo.GetPermissionByName(ctx, permission.Name)
+	if err == nil && existing.ID != permission.ID {
+		return ErrPermissionExists
+	}
+
+	return s.repo.UpdatePermission(ctx, permission)
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	return s.repo.DeletePermission(ctx, id)
+}
+
+// AccountHasPermission reports whether the account holds the named permission through one of its roles
+func (s *Service) AccountHasPermission(ctx context.Context, accountID int, name string) (bool, error) {
+	return s.repo.AccountHasPermission(ctx, accountID, name)
+}
+
+// GrantsForTornID returns the roles and permissions held by the account with the given Torn ID
+func (s *Service) GrantsForTornID(ctx context.Context, tornID int) (*Grants, error) {
+	return s.repo.GrantsForTornID(ctx, tornID)
+}
@@ -0,0 +1,203 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"kaizen-hq/internal/account"
+	"kaizen-hq/internal/permission"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// handleError maps service errors to HTTP responses
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrRoleNotFound), errors.Is(err, permission.ErrPermissionNotFound),
+		errors.Is(err, account.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrRoleExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// idParam reads a whole number path parameter, responding with 400 if it isn't one
+func idParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a whole number"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) List(c *gin.Context) {
+	roles, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	id, ok := idParam(c, "roleID")
+	if !ok {
+		return
+	}
+
+	role, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	perms, err := h.service.ListPermissions(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"role": role, "permissions": perms})
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	role, err := h.service.Create(c.Request.Context(), &Role{
+		Name:         req.Name,
+		Description:  req.Description,
+		IsLeadership: req.IsLeadership,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"role": role})
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := idParam(c, "roleID")
+	if !ok {
+		return
+	}
+
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	role := &Role{
+		ID:           id,
+		Name:         req.Name,
+		Description:  req.Description,
+		IsLeadership: req.IsLeadership,
+	}
+	if err := h.service.Update(c.Request.Context(), role); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"role": role})
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	id, ok := idParam(c, "roleID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListMembers(c *gin.Context) {
+	id, ok := idParam(c, "roleID")
+	if !ok {
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+func (h *Handler) AddMember(c *gin.Context) {
+	h.changeMember(c, h.service.AddMember)
+}
+
+func (h *Handler) RemoveMember(c *gin.Context) {
+	h.changeMember(c, h.service.RemoveMember)
+}
+
+func (h *Handler) changeMember(c *gin.Context, change func(ctx context.Context, roleID, tornID int) error) {
+	roleID, ok := idParam(c, "roleID")
+	if !ok {
+		return
+	}
+
+	tornID, ok := idParam(c, "tornID")
+	if !ok {
+		return
+	}
+
+	if err := change(c.Request.Context(), roleID, tornID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) GrantPermission(c *gin.Context) {
+	h.changePermission(c, h.service.GrantPermission)
+}
+
+func (h *Handler) RevokePermission(c *gin.Context) {
+	h.changePermission(c, h.service.RevokePermission)
+}
+
+func (h *Handler) changePermission(c *gin.Context, change func(ctx context.Context, roleID, permissionID int) error) {
+	roleID, ok := idParam(c, "roleID")
+	if !ok {
+		return
+	}
+
+	permissionID, ok := idParam(c, "permissionID")
+	if !ok {
+		return
+	}
+
+	if err := change(c.Request.Context(), roleID, permissionID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
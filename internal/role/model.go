@@ -1,8 +1,24 @@
 package role
 
+import "time"
+
 type Role struct {
 	ID           int    `json:"id"`
 	Name         string `json:"name"`
 	Description  string `json:"description"`
 	IsLeadership bool   `json:"is_leadership"`
 }
+
+// Member is an account holding a role
+type Member struct {
+	AccountID int       `json:"account_id"`
+	TornID    int       `json:"torn_id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RoleRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Description  string `json:"description"`
+	IsLeadership bool   `json:"is_leadership"`
+}
@@ -3,11 +3,14 @@ package role
 import (
 	"context"
 	"errors"
+	"kaizen-hq/internal/permission"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var ErrRoleNotFound = errors.New("role not found")
+
 type Repository struct {
 	db *pgxpool.Pool
 }
@@ -16,6 +19,22 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
+const roleColumns = `id, name, description, is_leadership`
+
+func scanRole(row pgx.Row) (*Role, error) {
+	role := &Role{}
+
+	err := row.Scan(&role.ID, &role.Name, &role.Description, &role.IsLeadership)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return role, nil
+}
+
 func (r *Repository) CreateRole(ctx context.Context, role *Role) (*Role, error) {
 	query := `INSERT INTO roles (name, description, is_leadership) VALUES ($1, $2, $3) RETURNING id`
 
@@ -25,24 +44,108 @@ func (r *Repository) CreateRole(ctx context.Context, role *Role) (*Role, error)
 }
 
 func (r *Repository) GetRoleByName(ctx context.Context, name string) (*Role, error) {
-	role := &Role{}
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE name = $1`
 
-	query := `SELECT * FROM roles WHERE name = $1`
-	err := r.db.QueryRow(ctx, query, name).Scan(role)
+	return scanRole(r.db.QueryRow(ctx, query, name))
+}
 
+func (r *Repository) GetRoleByID(ctx context.Context, id int) (*Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE id = $1`
+
+	return scanRole(r.db.QueryRow(ctx, query, id))
+}
+
+// ListRoles returns every role ordered by name
+func (r *Repository) ListRoles(ctx context.Context) ([]*Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles ORDER BY name`
+
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, errors.New("no role with that name found")
-		}
 		return nil, err
 	}
+	defer rows.Close()
 
-	return role, nil
+	roles := []*Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *Repository) UpdateRole(ctx context.Context, role *Role) error {
+	query := `UPDATE roles SET name = $1, description = $2, is_leadership = $3 WHERE id = $4`
+
+	tag, err := r.db.Exec(ctx, query, role.Name, role.Description, role.IsLeadership, role.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRoleNotFound
+	}
+
+	return nil
+}
+
+// DeleteRole removes a role along with its permission grants and memberships
+func (r *Repository) DeleteRole(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM roles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRoleNotFound
+	}
+
+	return nil
 }
 
 func (r *Repository) AssignPermission(ctx context.Context, roleID int, permissionID int) error {
-	query := `INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2)`
+	query := `INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	_, err := r.db.Exec(ctx, query, roleID, permissionID)
+	return err
+}
+
+func (r *Repository) RevokePermission(ctx context.Context, roleID int, permissionID int) error {
+	query := `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`
 
 	_, err := r.db.Exec(ctx, query, roleID, permissionID)
 	return err
 }
+
+// ListPermissions returns the permissions granted to a role
+func (r *Repository) ListPermissions(ctx context.Context, roleID int) ([]permission.Permission, error) {
+	query := `SELECT p.id, p.name, p.description
+		FROM role_permissions rp
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE rp.role_id = $1
+		ORDER BY p.name`
+
+	rows, err := r.db.Query(ctx, query, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[permission.Permission])
+}
+
+// ListMembers returns the accounts holding a role
+func (r *Repository) ListMembers(ctx context.Context, roleID int) ([]Member, error) {
+	query := `SELECT a.id, a.torn_id, a.email, a.created_at
+		FROM user_roles ur
+		JOIN accounts a ON a.id = ur.user_id
+		WHERE ur.role_id = $1
+		ORDER BY a.torn_id`
+
+	rows, err := r.db.Query(ctx, query, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Member])
+}
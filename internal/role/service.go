@@ -3,29 +3,193 @@ package role
 import (
 	"context"
 	"errors"
+	"fmt"
 	"kaizen-hq/config"
+	"kaizen-hq/internal/account"
 	"kaizen-hq/internal/permission"
+	"slices"
 )
 
+var ErrRoleExists = errors.New("role already exists")
+
 type Service struct {
-	repo   *Repository
-	config *config.Config
+	repo       *Repository
+	config     *config.Config
+	permission *permission.Service
+	account    *account.Service
 }
 
-func NewService(repo *Repository, cfg *config.Config) *Service {
-	return &Service{repo: repo, config: cfg}
+func NewService(repo *Repository, cfg *config.Config, permissionService *permission.Service, accountService *account.Service) *Service {
+	return &Service{repo: repo, config: cfg, permission: permissionService, account: accountService}
 }
 
 func (s *Service) Create(ctx context.Context, role *Role) (*Role, error) {
 	// Check if the role already exists
 	_, err := s.repo.GetRoleByName(ctx, role.Name)
 	if err == nil {
-		return nil, errors.New("role already exists")
+		return nil, ErrRoleExists
 	}
 
 	return s.repo.CreateRole(ctx, role)
 }
 
+// Ensure creates the role if no role has its name yet, otherwise updates the
+// existing role's description and leadership flag to match
+func (s *Service) Ensure(ctx context.Context, role *Role) (*Role, error) {
+	existing, err := s.repo.GetRoleByName(ctx, role.Name)
+	if errors.Is(err, ErrRoleNotFound) {
+		return s.repo.CreateRole(ctx, role)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	role.ID = existing.ID
+	if *existing == *role {
+		return role, nil
+	}
+
+	return role, s.repo.UpdateRole(ctx, role)
+}
+
+func (s *Service) Get(ctx context.Context, id int) (*Role, error) {
+	return s.repo.GetRoleByID(ctx, id)
+}
+
+func (s *Service) GetByName(ctx context.Context, name string) (*Role, error) {
+	return s.repo.GetRoleByName(ctx, name)
+}
+
+func (s *Service) List(ctx context.Context) ([]*Role, error) {
+	return s.repo.ListRoles(ctx)
+}
+
+// Update changes a role's details, refusing to take another role's name
+func (s *Service) Update(ctx context.Context, role *Role) error {
+	existing, err := s.repo.GetRoleByName(ctx, role.Name)
+	if err == nil && existing.ID != role.ID {
+		return ErrRoleExists
+	}
+
+	return s.repo.UpdateRole(ctx, role)
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	return s.repo.DeleteRole(ctx, id)
+}
+
 func (s *Service) AssignPermission(ctx context.Context, role *Role, permission *permission.Permission) error {
 	return s.repo.AssignPermission(ctx, role.ID, permission.ID)
 }
+
+// GrantPermission gives a role a permission, both looked up by ID
+func (s *Service) GrantPermission(ctx context.Context, roleID, permissionID int) error {
+	role, perm, err := s.lookup(ctx, roleID, permissionID)
+	if err != nil {
+		return err
+	}
+
+	return s.AssignPermission(ctx, role, perm)
+}
+
+// RevokePermission takes a permission away from a role
+func (s *Service) RevokePermission(ctx context.Context, roleID, permissionID int) error {
+	role, perm, err := s.lookup(ctx, roleID, permissionID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RevokePermission(ctx, role.ID, perm.ID)
+}
+
+// SetPermissions makes the named permissions the only ones the role grants
+func (s *Service) SetPermissions(ctx context.Context, roleID int, names []string) error {
+	current, err := s.ListPermissions(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	for _, perm := range current {
+		if !slices.Contains(names, perm.Name) {
+			if err := s.repo.RevokePermission(ctx, roleID, perm.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range names {
+		perm, err := s.permission.GetByName(ctx, name)
+		if err != nil {
+			return fmt.Errorf("permission %q: %w", name, err)
+		}
+		if err := s.repo.AssignPermission(ctx, roleID, perm.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ListPermissions(ctx context.Context, roleID int) ([]permission.Permission, error) {
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListPermissions(ctx, roleID)
+}
+
+func (s *Service) ListMembers(ctx context.Context, roleID int) ([]Member, error) {
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListMembers(ctx, roleID)
+}
+
+// AddMember gives the account with the given Torn ID a role
+func (s *Service) AddMember(ctx context.Context, roleID, tornID int) error {
+	role, acc, err := s.lookupMember(ctx, roleID, tornID)
+	if err != nil {
+		return err
+	}
+
+	return s.account.AssignRole(ctx, acc.ID, role.ID)
+}
+
+// RemoveMember takes a role away from the account with the given Torn ID
+func (s *Service) RemoveMember(ctx context.Context, roleID, tornID int) error {
+	role, acc, err := s.lookupMember(ctx, roleID, tornID)
+	if err != nil {
+		return err
+	}
+
+	return s.account.RevokeRole(ctx, acc.ID, role.ID)
+}
+
+func (s *Service) lookupMember(ctx context.Context, roleID, tornID int) (*Role, *account.Account, error) {
+	role, err := s.repo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acc, err := s.account.GetAccountByTornID(ctx, tornID, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return role, acc, nil
+}
+
+func (s *Service) lookup(ctx context.Context, roleID, permissionID int) (*Role, *permission.Permission, error) {
+	role, err := s.repo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	perm, err := s.permission.Get(ctx, permissionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return role, perm, nil
+}
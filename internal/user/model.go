@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 type User struct {
 	Rank         string `json:"rank"`
 	Level        int    `json:"level"`
@@ -20,4 +22,14 @@ type User struct {
 	PropertyID   int    `json:"property_id"`
 	Revivable    int    `json:"revivable"`
 	ProfileImage string `json:"profile_image"`
-}
\ No newline at end of file
+	DiscordID    string `json:"discord_id,omitempty"`
+
+	LastActionStatus string    `json:"last_action_status"`
+	LastActionAt     time.Time `json:"last_action_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// IsStale reports whether the stored profile is older than maxAge
+func (u *User) IsStale(maxAge time.Duration) bool {
+	return time.Since(u.UpdatedAt) > maxAge
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,9 +21,9 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 }
 
 func (r *Repository) CreateUser(ctx context.Context, user User) error {
-	query := `INSERT INTO users (rank, level, honor, gender, property, signup, awards, friends, enemies, forum_posts, karma, age, role, donator, player_id, name, property_id, revivable, profile_image) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19) RETURNING player_id`
+	query := `INSERT INTO users (rank, level, honor, gender, property, signup, awards, friends, enemies, forum_posts, karma, age, role, donator, player_id, name, property_id, revivable, profile_image, last_action_status, last_action_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING player_id`
 
-	err := r.db.QueryRow(ctx, query, user.Rank, user.Level, user.Honor, user.Gender, user.Property, user.Signup, user.Awards, user.Friends, user.Enemies, user.ForumPosts, user.Karma, user.Age, user.Role, user.Donator, user.PlayerID, user.Name, user.PropertyID, user.Revivable, user.ProfileImage).Scan(&user.PlayerID)
+	err := r.db.QueryRow(ctx, query, user.Rank, user.Level, user.Honor, user.Gender, user.Property, user.Signup, user.Awards, user.Friends, user.Enemies, user.ForumPosts, user.Karma, user.Age, user.Role, user.Donator, user.PlayerID, user.Name, user.PropertyID, user.Revivable, user.ProfileImage, user.LastActionStatus, user.LastActionAt, time.Now()).Scan(&user.PlayerID)
 
 	if err != nil {
 		fmt.Println(err)
@@ -32,33 +33,75 @@ func (r *Repository) CreateUser(ctx context.Context, user User) error {
 	return nil
 }
 
-func (r *Repository) GetUserByPlayerID(ctx context.Context, id int) (*User, error) {
-	user := &User{}
+const userColumns = `rank, level, honor, gender, property, signup, awards, friends, enemies, forum_posts,
+	karma, age, role, donator, player_id, name, property_id, revivable, profile_image, COALESCE(discord_id, ''),
+	last_action_status, last_action_at, updated_at`
 
-	query := `SELECT * FROM users WHERE player_id = $1`
-	err := r.db.QueryRow(ctx, query, id).Scan(&user)
+func scanUser(row pgx.Row) (*User, error) {
+	user := &User{}
 
+	err := row.Scan(
+		&user.Rank, &user.Level, &user.Honor, &user.Gender, &user.Property, &user.Signup,
+		&user.Awards, &user.Friends, &user.Enemies, &user.ForumPosts, &user.Karma, &user.Age,
+		&user.Role, &user.Donator, &user.PlayerID, &user.Name, &user.PropertyID, &user.Revivable,
+		&user.ProfileImage, &user.DiscordID, &user.LastActionStatus, &user.LastActionAt, &user.UpdatedAt,
+	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrProfileNotFound
 		}
+		return nil, err
 	}
 
 	return user, nil
 }
 
+func (r *Repository) GetUserByPlayerID(ctx context.Context, id int) (*User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE player_id = $1`
+
+	return scanUser(r.db.QueryRow(ctx, query, id))
+}
+
+// GetUserByDiscordID finds the player linked to a Discord account
+func (r *Repository) GetUserByDiscordID(ctx context.Context, discordID string) (*User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE discord_id = $1`
+
+	return scanUser(r.db.QueryRow(ctx, query, discordID))
+}
+
+// LinkDiscord links a Discord account to a player, unlinking it from anyone else first
+func (r *Repository) LinkDiscord(ctx context.Context, playerID int, discordID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET discord_id = NULL WHERE discord_id = $1 AND player_id <> $2`, discordID, playerID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET discord_id = $1 WHERE player_id = $2`, discordID, playerID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (r *Repository) UpdateUser(ctx context.Context, user User) error {
 	query := `UPDATE users
            SET rank = $1, level = $2, honor = $3, gender = $4, property = $5,
                signup = $6, awards = $7, friends = $8, enemies = $9, forum_posts = $10,
-               karma = $11, age = $12, role = $13, donator = $14, name = $15, property_id = $16, revivable = $17
-           WHERE player_id = $18
+               karma = $11, age = $12, role = $13, donator = $14, name = $15, property_id = $16, revivable = $17,
+               profile_image = $18, last_action_status = $19, last_action_at = $20, updated_at = $21
+           WHERE player_id = $22
            RETURNING player_id`
 
 	params := []any{
 		user.Rank, user.Level, user.Honor, user.Gender, user.Property, user.Signup,
 		user.Awards, user.Friends, user.Enemies, user.ForumPosts, user.Karma, user.Age,
-		user.Role, user.Donator, user.Name, user.PropertyID, user.Revivable, user.PlayerID,
+		user.Role, user.Donator, user.Name, user.PropertyID, user.Revivable,
+		user.ProfileImage, user.LastActionStatus, user.LastActionAt, time.Now(), user.PlayerID,
 	}
 
 	err := r.db.QueryRow(ctx, query, params...).Scan(&user.PlayerID)
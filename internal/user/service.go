@@ -5,17 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"kaizen-hq/config"
+	"kaizen-hq/internal/account"
 	"kaizen-hq/internal/client"
+	"log"
+	"strconv"
+	"time"
 )
 
+// UserStaleAfter is how old a stored profile may get before it is refreshed from Torn
+const UserStaleAfter = 6 * time.Hour
+
 type Service struct {
-	repo       *Repository
-	config     *config.Config
-	tornClient client.Client
+	repo           *Repository
+	config         *config.Config
+	accountService *account.Service
+	tornClient     client.Client
 }
 
-func NewService(repo *Repository, cfg *config.Config, tornClient client.Client) *Service {
-	return &Service{repo: repo, config: cfg, tornClient: tornClient}
+func NewService(repo *Repository, cfg *config.Config, accountService *account.Service, tornClient client.Client) *Service {
+	return &Service{repo: repo, config: cfg, accountService: accountService, tornClient: tornClient}
 }
 
 func (s *Service) GetUserByPlayerID(
@@ -30,6 +38,38 @@ func (s *Service) GetUserByPlayerID(
 	return user, nil
 }
 
+func (s *Service) GetUserByDiscordID(ctx context.Context, discordID string) (*User, error) {
+	return s.repo.GetUserByDiscordID(ctx, discordID)
+}
+
+// VerifyDiscord resolves the Torn player linked to a Discord account through
+// the Torn discord selection and stores the link on the player and account
+func (s *Service) VerifyDiscord(ctx context.Context, discordID string) (*User, error) {
+	tornID, err := s.tornClient.FetchTornIDByDiscordID(ctx, client.PooledKey, discordID)
+	if err != nil {
+		if errors.Is(err, client.ErrDiscordNotLinked) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to verify discord account: %w", err)
+	}
+
+	user, err := s.EnsureUserExists(ctx, strconv.Itoa(tornID), client.PooledKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.LinkDiscord(ctx, tornID, discordID); err != nil {
+		return nil, fmt.Errorf("failed to link discord account: %w", err)
+	}
+
+	if err := s.accountService.LinkDiscord(ctx, tornID, discordID); err != nil {
+		return nil, fmt.Errorf("failed to link discord account: %w", err)
+	}
+
+	user.DiscordID = discordID
+	return user, nil
+}
+
 func (s *Service) CreateUserIfNotExists(ctx context.Context, tornUser *client.User) error {
 	// Check if user already exists
 	_, err := s.repo.GetUserByPlayerID(ctx, tornUser.PlayerID)
@@ -41,29 +81,76 @@ func (s *Service) CreateUserIfNotExists(ctx context.Context, tornUser *client.Us
 		return fmt.Errorf("failed to check if user exists: %w", err)
 	}
 
+	return s.repo.CreateUser(ctx, fromTornUser(tornUser))
+}
+
+// GetProfile returns the stored profile linked to a Discord account,
+// refreshing it from Torn first if it is older than UserStaleAfter
+func (s *Service) GetProfile(ctx context.Context, discordID string) (*User, error) {
+	user, err := s.repo.GetUserByDiscordID(ctx, discordID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsStale(UserStaleAfter) {
+		return user, nil
+	}
+
+	refreshed, err := s.RefreshUser(ctx, user.PlayerID)
+	if err != nil {
+		// A slightly old profile is better than none
+		log.Printf("Error refreshing user %d: %v", user.PlayerID, err)
+		return user, nil
+	}
+
+	return refreshed, nil
+}
+
+// RefreshUser re-fetches a player's profile from Torn and stores it
+func (s *Service) RefreshUser(ctx context.Context, playerID int) (*User, error) {
+	tornUser, err := s.tornClient.FetchTornUser(ctx, client.PooledKey, strconv.Itoa(playerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch torn user: %w", err)
+	}
+
+	user := fromTornUser(tornUser)
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserByPlayerID(ctx, playerID)
+}
+
+// fromTornUser converts a Torn API profile into a stored user
+func fromTornUser(tornUser *client.User) User {
 	user := User{
-		Rank:         tornUser.Rank,
-		Level:        tornUser.Level,
-		Honor:        tornUser.Honor,
-		Gender:       tornUser.Gender,
-		Property:     tornUser.Property,
-		Signup:       tornUser.Signup,
-		Awards:       tornUser.Awards,
-		Friends:      tornUser.Friends,
-		Enemies:      tornUser.Enemies,
-		ForumPosts:   tornUser.ForumPosts,
-		Karma:        tornUser.Karma,
-		Age:          tornUser.Age,
-		Role:         tornUser.Role,
-		Donator:      tornUser.Donator,
-		PlayerID:     tornUser.PlayerID,
-		Name:         tornUser.Name,
-		PropertyID:   tornUser.PropertyID,
-		Revivable:    tornUser.Revivable,
-		ProfileImage: tornUser.ProfileImage,
-	}
-
-	return s.repo.CreateUser(ctx, user)
+		Rank:             tornUser.Rank,
+		Level:            tornUser.Level,
+		Honor:            tornUser.Honor,
+		Gender:           tornUser.Gender,
+		Property:         tornUser.Property,
+		Signup:           tornUser.Signup,
+		Awards:           tornUser.Awards,
+		Friends:          tornUser.Friends,
+		Enemies:          tornUser.Enemies,
+		ForumPosts:       tornUser.ForumPosts,
+		Karma:            tornUser.Karma,
+		Age:              tornUser.Age,
+		Role:             tornUser.Role,
+		Donator:          tornUser.Donator,
+		PlayerID:         tornUser.PlayerID,
+		Name:             tornUser.Name,
+		PropertyID:       tornUser.PropertyID,
+		Revivable:        tornUser.Revivable,
+		ProfileImage:     tornUser.ProfileImage,
+		LastActionStatus: tornUser.LastAction.Status,
+	}
+
+	if tornUser.LastAction.Timestamp > 0 {
+		user.LastActionAt = time.Unix(tornUser.LastAction.Timestamp, 0)
+	}
+
+	return user
 }
 
 func (s *Service) EnsureUserExists(ctx context.Context, tornID string, apiKey string) (*User, error) {
@@ -6,11 +6,15 @@ import (
 	"kaizen-hq/bootstrap"
 	"kaizen-hq/config"
 	"kaizen-hq/internal/account"
+	"kaizen-hq/internal/apicache"
 	"kaizen-hq/internal/auth"
+	"kaizen-hq/internal/banker"
 	"kaizen-hq/internal/bot"
 	"kaizen-hq/internal/client"
 	"kaizen-hq/internal/database"
 	"kaizen-hq/internal/faction"
+	"kaizen-hq/internal/guild"
+	"kaizen-hq/internal/job"
 	"kaizen-hq/internal/permission"
 	"kaizen-hq/internal/role"
 	"kaizen-hq/internal/user"
@@ -18,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -32,9 +37,24 @@ import (
 
 var BotID string
 
-func RunMidnightTask(factionService *faction.Service) {
+// gymSnapshotJob is the job run name under which gym energy snapshots are recorded
+const gymSnapshotJob = "gym_energy_snapshot"
+
+// gymQuotaJob is the job run name under which weekly gym quota evaluations are recorded
+const gymQuotaJob = "gym_quota_evaluation"
+
+// midnightTaskTimeout bounds how long the midnight task may take
+const midnightTaskTimeout = 10 * time.Minute
+
+func RunMidnightTask(ctx context.Context, services *Services) {
 	fmt.Println("Running task at:", time.Now().UTC())
-	// factionService.UpdateGymEnergy()
+
+	ctx, cancel := context.WithTimeout(ctx, midnightTaskTimeout)
+	defer cancel()
+
+	if err := services.Job.Track(ctx, gymSnapshotJob, services.Faction.SnapshotGymEnergy); err != nil {
+		log.Printf("Error running midnight task: %v", err)
+	}
 }
 
 func main() {
@@ -44,6 +64,15 @@ func main() {
 
 	// Load application configuration
 	cfg := config.Load()
+
+	// `migrate` manages the schema without starting the app
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
 	if err := validateConfig(cfg); err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
@@ -165,8 +194,15 @@ func initializeApp(ctx context.Context, cfg *config.Config) (*App, error) {
 	repos := initializeRepositories(db)
 	services := initializeServices(repos, cfg)
 
+	// Bring permissions and default roles in line with the catalog
+	report, err := bootstrap.ReconcileCatalog(ctx, services.Role, services.Permission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile role catalog: %w", err)
+	}
+	log.Printf("Role catalog: %s", report)
+
 	// Seed system data if needed
-	if err := bootstrap.SeedSystem(ctx, services.Account, services.User, services.Role, services.Permission); err != nil {
+	if err := bootstrap.SeedSystem(ctx, cfg, services.TornClient, services.Account, services.User, services.Role, services.Permission); err != nil {
 		return nil, fmt.Errorf("failed to seed system data: %w", err)
 	}
 
@@ -177,31 +213,97 @@ func initializeApp(ctx context.Context, cfg *config.Config) (*App, error) {
 	}
 	app.HTTPServer = server
 
-	// Initialize scheduler
-	scheduler, err := initializeScheduler(services)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize scheduler: %w", err)
-	}
-	app.Scheduler = scheduler
-
 	// Initialize Discord bot
-	bot, err := initializeBot(cfg.DiscordBotToken)
+	bot, err := initializeBot(cfg.DiscordBotToken, services)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize bot: %w", err)
 	}
 	app.Bot = bot
 
+	// Stop using keys Torn rejects for good and let their owners know
+	services.KeyPool.OnInvalidKey(bot.HandleInvalidKey)
+
+	// Initialize scheduler
+	scheduler, err := initializeScheduler(ctx, services, bot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize scheduler: %w", err)
+	}
+	app.Scheduler = scheduler
+
 	return app, nil
 }
 
 // initializeBot creates and configures the Discord bot
-func initializeBot(token string) (*bot.Bot, error) {
-	return bot.NewBot(token)
+func initializeBot(token string, services *Services) (*bot.Bot, error) {
+	return bot.NewBot(token, bot.Services{
+		Account:    services.Account,
+		User:       services.User,
+		Faction:    services.Faction,
+		Role:       services.Role,
+		Permission: services.Permission,
+		Banker:     services.Banker,
+		Guild:      services.Guild,
+		TornClient: services.TornClient,
+		TornStats:  services.TornStats,
+	})
 }
 
-// initializeDB sets up the database connection
+// initializeDB sets up the database connection and brings the schema up to date
 func initializeDB(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
-	return database.NewDB(ctx, cfg)
+	db, err := database.NewDB(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.MigrateUp(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// runMigrate handles `migrate up`, `migrate down [steps]` and `migrate status`
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	db, err := database.NewDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch command {
+	case "up":
+		return database.MigrateUp(ctx, db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("steps must be a positive whole number")
+			}
+		}
+		return database.MigrateDown(ctx, db, steps)
+	case "status":
+		states, err := database.MigrationStatus(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", state.Version, state.Name, applied)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", command)
+	}
 }
 
 // Repositories holds all data access repositories
@@ -211,6 +313,10 @@ type Repositories struct {
 	Faction    *faction.Repository
 	Role       *role.Repository
 	Permission *permission.Repository
+	Banker     *banker.Repository
+	Guild      *guild.Repository
+	Job        *job.Repository
+	TornCache  *apicache.Repository
 }
 
 // initializeRepositories creates all data repositories
@@ -221,6 +327,10 @@ func initializeRepositories(db *pgxpool.Pool) *Repositories {
 		Faction:    faction.NewRepository(db),
 		Role:       role.NewRepository(db),
 		Permission: permission.NewRepository(db),
+		Banker:     banker.NewRepository(db),
+		Guild:      guild.NewRepository(db),
+		Job:        job.NewRepository(db),
+		TornCache:  apicache.NewRepository(db),
 	}
 }
 
@@ -232,19 +342,43 @@ type Services struct {
 	Faction    *faction.Service
 	Role       *role.Service
 	Permission *permission.Service
+	Banker     *banker.Service
+	Guild      *guild.Service
+	Job        *job.Service
 	TornClient client.Client
+	KeyPool    *client.KeyPool
+	TornCache  *client.Cache
+	TornStats  client.TornStatsClient
 }
 
 // initializeServices creates all business logic services
 func initializeServices(repos *Repositories, cfg *config.Config) *Services {
-	tornClient := client.NewClient()
-
 	accountService := account.NewService(repos.Account, cfg)
-	userService := user.NewService(repos.User, cfg, tornClient)
+
+	// Every service shares one pool so each key's rate limit covers all of them
+	keyPool := client.NewKeyPool(
+		client.NewClient(client.WithVersion(cfg.TornAPI.Version)),
+		accountService.FactionAPIKeys,
+		client.WithRateLimit(cfg.TornAPI.KeyRateLimit),
+		client.WithMaxWait(cfg.TornAPI.KeyMaxWait),
+		client.WithRetry(3, 2*time.Second),
+	)
+
+	// Cache in front of the pool so repeated lookups don't use up the keys
+	var cacheOpts []client.CacheOption
+	if cfg.TornAPI.CachePersist {
+		cacheOpts = append(cacheOpts, client.WithCacheStore(repos.TornCache))
+	}
+	tornClient := client.NewCache(keyPool, cacheOpts...)
+
+	userService := user.NewService(repos.User, cfg, accountService, tornClient)
 	authService := auth.NewService(accountService, userService, cfg, tornClient)
-	factionService := faction.NewService(repos.Faction, cfg, tornClient)
-	roleService := role.NewService(repos.Role, cfg)
 	permissionService := permission.NewService(repos.Permission, cfg)
+	roleService := role.NewService(repos.Role, cfg, permissionService, accountService)
+	factionService := faction.NewService(repos.Faction, cfg, tornClient, accountService, roleService)
+	bankerService := banker.NewService(repos.Banker, cfg, accountService, userService, tornClient)
+	guildService := guild.NewService(repos.Guild, cfg)
+	jobService := job.NewService(repos.Job, cfg)
 
 	return &Services{
 		Account:    accountService,
@@ -253,7 +387,13 @@ func initializeServices(repos *Repositories, cfg *config.Config) *Services {
 		Faction:    factionService,
 		Role:       roleService,
 		Permission: permissionService,
+		Banker:     bankerService,
+		Guild:      guildService,
+		Job:        jobService,
 		TornClient: tornClient,
+		KeyPool:    keyPool,
+		TornCache:  tornClient,
+		TornStats:  client.NewTornStatsClient(),
 	}
 }
 
@@ -268,9 +408,15 @@ func initializeHTTPServer(cfg *config.Config, services *Services) (*http.Server,
 	// Create handlers
 	authHandler := auth.NewHandler(services.Auth)
 	accountHandler := account.NewHandler(services.Account)
+	bankerHandler := banker.NewHandler(services.Banker)
+	roleHandler := role.NewHandler(services.Role)
+	permissionHandler := permission.NewHandler(services.Permission)
+	factionHandler := faction.NewHandler(services.Faction)
+	cacheHandler := apicache.NewHandler(services.TornCache)
+	jobHandler := job.NewHandler(services.Job)
 
 	// Register routes
-	registerRoutes(router, authHandler, accountHandler, cfg)
+	registerRoutes(router, authHandler, accountHandler, bankerHandler, roleHandler, permissionHandler, factionHandler, cacheHandler, jobHandler, services, cfg)
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
@@ -288,7 +434,19 @@ func initializeHTTPServer(cfg *config.Config, services *Services) (*http.Server,
 }
 
 // registerRoutes configures all API endpoints
-func registerRoutes(r *gin.Engine, authHandler *auth.Handler, userHandler *account.Handler, cfg *config.Config) {
+func registerRoutes(
+	r *gin.Engine,
+	authHandler *auth.Handler,
+	userHandler *account.Handler,
+	bankerHandler *banker.Handler,
+	roleHandler *role.Handler,
+	permissionHandler *permission.Handler,
+	factionHandler *faction.Handler,
+	cacheHandler *apicache.Handler,
+	jobHandler *job.Handler,
+	services *Services,
+	cfg *config.Config,
+) {
 	// Public routes
 	r.POST("/register", authHandler.Register)
 	r.POST("/login", authHandler.Login)
@@ -298,12 +456,58 @@ func registerRoutes(r *gin.Engine, authHandler *auth.Handler, userHandler *accou
 	protected.Use(auth.AuthMiddleware(cfg))
 	{
 		protected.GET("/user/:tornID", userHandler.GetAccountByTornID)
+		protected.PUT("/account/api-key", authHandler.UpdateAPIKey)
+		protected.GET("/faction/members", auth.RequirePermission(services.Permission, permission.ViewLogs), factionHandler.ListMembers)
+		protected.GET("/faction/crimes", auth.RequirePermission(services.Permission, permission.ViewLogs), factionHandler.Crimes)
+		protected.GET("/faction/gym", auth.RequirePermission(services.Permission, permission.ViewLogs), factionHandler.GymLeaderboard)
+		protected.GET("/faction/gym/:tornID", auth.RequirePermission(services.Permission, permission.ViewLogs), factionHandler.MemberGym)
 		// Add more protected routes here
 	}
+
+	// Admin routes, each gated on the permission it needs
+	admin := protected.Group("/admin")
+	{
+		admin.GET("/banker/requests", auth.RequirePermission(services.Permission, permission.ViewLogs), bankerHandler.ListRequests)
+		admin.GET("/torn/cache", auth.RequirePermission(services.Permission, permission.ViewLogs), cacheHandler.Stats)
+		admin.GET("/jobs/:name/runs", auth.RequirePermission(services.Permission, permission.ViewLogs), jobHandler.ListRuns)
+	}
+
+	// Gym quotas and leaves of absence
+	gym := admin.Group("/gym", auth.RequirePermission(services.Permission, permission.GymManage))
+	{
+		gym.GET("/quotas", factionHandler.ListGymQuotas)
+		gym.PUT("/quotas", factionHandler.SetGymQuota)
+		gym.DELETE("/quotas/:quotaID", factionHandler.DeleteGymQuota)
+		gym.GET("/leaves", factionHandler.ListLeaves)
+		gym.POST("/leaves", factionHandler.RecordLeave)
+		gym.DELETE("/leaves/:leaveID", factionHandler.DeleteLeave)
+		gym.GET("/evaluation", factionHandler.GymEvaluation)
+	}
+
+	// Role and permission management
+	rbac := admin.Group("/", auth.RequirePermission(services.Permission, permission.RolesManage))
+	{
+		rbac.GET("/roles", roleHandler.List)
+		rbac.POST("/roles", roleHandler.Create)
+		rbac.GET("/roles/:roleID", roleHandler.Get)
+		rbac.PUT("/roles/:roleID", roleHandler.Update)
+		rbac.DELETE("/roles/:roleID", roleHandler.Delete)
+		rbac.GET("/roles/:roleID/members", roleHandler.ListMembers)
+		rbac.PUT("/roles/:roleID/members/:tornID", roleHandler.AddMember)
+		rbac.DELETE("/roles/:roleID/members/:tornID", roleHandler.RemoveMember)
+		rbac.PUT("/roles/:roleID/permissions/:permissionID", roleHandler.GrantPermission)
+		rbac.DELETE("/roles/:roleID/permissions/:permissionID", roleHandler.RevokePermission)
+
+		rbac.GET("/permissions", permissionHandler.List)
+		rbac.POST("/permissions", permissionHandler.Create)
+		rbac.GET("/permissions/:permissionID", permissionHandler.Get)
+		rbac.PUT("/permissions/:permissionID", permissionHandler.Update)
+		rbac.DELETE("/permissions/:permissionID", permissionHandler.Delete)
+	}
 }
 
 // initializeScheduler sets up scheduled tasks
-func initializeScheduler(services *Services) (gocron.Scheduler, error) {
+func initializeScheduler(ctx context.Context, services *Services, bot *bot.Bot) (gocron.Scheduler, error) {
 	// Create scheduler with UTC timezone
 	location, err := time.LoadLocation("UTC")
 	if err != nil {
@@ -326,14 +530,96 @@ func initializeScheduler(services *Services) (gocron.Scheduler, error) {
 				waitForSecond := time.Second * time.Duration(60-currentTime.Second())
 				time.Sleep(waitForSecond)
 			}
-			// Run the midnight task with faction service
-			RunMidnightTask(services.Faction)
+			// Run the midnight task, which stops early if the app shuts down
+			RunMidnightTask(ctx, services)
 		}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error scheduling midnight task: %w", err)
 	}
 
+	// Hold members to their gym quota once the Monday snapshot is in
+	_, err = scheduler.NewJob(
+		gocron.CronJob("30 0 * * 1", false),
+		gocron.NewTask(func() {
+			if err := services.Job.Track(ctx, gymQuotaJob, bot.EvaluateGymQuotas); err != nil {
+				log.Printf("Error evaluating gym quotas: %v", err)
+			}
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling gym quota evaluation: %w", err)
+	}
+
+	// Mirror in-game faction positions onto roles
+	_, err = scheduler.NewJob(
+		gocron.DurationJob(1*time.Hour),
+		gocron.NewTask(func() {
+			result, err := services.Faction.SyncPositions(ctx)
+			if err != nil {
+				log.Printf("Error syncing faction positions: %v", err)
+				return
+			}
+			log.Printf("Synced %d faction positions: %d roles assigned, %d removed", result.Roles, result.Assigned, result.Removed)
+		}),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling faction position sync: %w", err)
+	}
+
+	// Keep the faction roster and its join/leave history up to date
+	_, err = scheduler.NewJob(
+		gocron.DurationJob(30*time.Minute),
+		gocron.NewTask(func() {
+			result, err := services.Faction.SyncMembers(ctx)
+			if err != nil {
+				log.Printf("Error syncing faction members: %v", err)
+				return
+			}
+			log.Printf("Synced %d faction members: %d joined, %d left", result.Members, len(result.Joined), len(result.Left))
+		}),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling faction member sync: %w", err)
+	}
+
+	// Expire banker requests nobody picked up
+	_, err = scheduler.NewJob(
+		gocron.DurationJob(5*time.Minute),
+		gocron.NewTask(func() {
+			bot.ExpireBankerRequests(ctx)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling banker expiry task: %w", err)
+	}
+
+	// Match paid banker requests against the faction funds news
+	_, err = scheduler.NewJob(
+		gocron.DurationJob(5*time.Minute),
+		gocron.NewTask(func() {
+			bot.VerifyBankerPayouts(ctx)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling banker verification task: %w", err)
+	}
+
+	// Drop cached Torn responses that have expired
+	_, err = scheduler.NewJob(
+		gocron.DurationJob(1*time.Hour),
+		gocron.NewTask(func() {
+			if err := services.TornCache.PurgeExpired(ctx); err != nil {
+				log.Printf("Error purging the Torn response cache: %v", err)
+			}
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling torn cache purge: %w", err)
+	}
+
 	return scheduler, nil
 }
 